@@ -1,8 +1,15 @@
 package rq
 
 import (
+	"context"
 	"encoding/base64"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
 )
 
 // AuthProvider defines the interface for authentication providers
@@ -20,7 +27,7 @@ func (r *Request) Auth(authType, credentials string) *Request {
 	if r.err != nil {
 		return r
 	}
-	r.headers.Set("Authorization", authType+" "+credentials)
+	r.header().Set("Authorization", authType+" "+credentials)
 	return r
 }
 
@@ -47,7 +54,7 @@ func (r *Request) BasicAuth(username, password string) *Request {
 	if r.err != nil {
 		return r
 	}
-	r.headers.Set("Authorization", "Basic "+basicAuth(username, password))
+	r.header().Set("Authorization", "Basic "+basicAuth(username, password))
 	return r
 }
 
@@ -61,12 +68,175 @@ func (r *Request) BearerToken(token string) *Request {
 	if r.err != nil {
 		return r
 	}
-	r.headers.Set("Authorization", "Bearer "+token)
+	r.header().Set("Authorization", "Bearer "+token)
 	return r
 }
 
+// APIKeyLocation is where APIKeyAuth places the key
+type APIKeyLocation int
+
+const (
+	// InHeader sends the key as a request header
+	InHeader APIKeyLocation = iota
+	// InQuery sends the key as a query parameter
+	InQuery
+)
+
+// APIKeyAuth is an AuthProvider that sends an API key under the name Key,
+// either as a header or as a query parameter. In defaults to InHeader.
+type APIKeyAuth struct {
+	Key   string
+	Value string
+	In    APIKeyLocation
+}
+
+// Apply adds the API key to the request
+func (p APIKeyAuth) Apply(r *Request) *Request {
+	if r.err != nil {
+		return r
+	}
+	if p.Key == "" || p.Value == "" {
+		r.err = errors.New("api key: key and value are required")
+		return r
+	}
+
+	switch p.In {
+	case InHeader:
+		return r.Header(p.Key, p.Value)
+	case InQuery:
+		return r.QueryParam(p.Key, p.Value)
+	default:
+		r.err = fmt.Errorf("api key: unsupported location %d", p.In)
+		return r
+	}
+}
+
 // basicAuth creates a basic auth string from username and password
 func basicAuth(username, password string) string {
 	auth := fmt.Sprintf("%s:%s", username, password)
 	return base64.StdEncoding.EncodeToString([]byte(auth))
 }
+
+// oauth2ExpiryDelta is how long before the reported expiry a token is refreshed
+const oauth2ExpiryDelta = 10 * time.Second
+
+// OAuth2ClientCredentials is an AuthProvider implementing the OAuth2
+// client credentials grant. Tokens are cached and refreshed once expired.
+// It is safe to share one provider across goroutines; concurrent callers
+// wait for a single token fetch.
+type OAuth2ClientCredentials struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       []string
+	HTTPClient   *http.Client
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+
+	// refreshing is closed once the token fetch in progress finishes
+	refreshing chan struct{}
+}
+
+// Apply injects a Bearer token when the request is sent, fetching a new one
+// if needed with the context of the outgoing request
+func (p *OAuth2ClientCredentials) Apply(r *Request) *Request {
+	if r.err != nil {
+		return r
+	}
+
+	r.beforeSend = append(r.beforeSend, func(req *http.Request) error {
+		token, err := p.TokenContext(req.Context())
+		if err != nil {
+			return fmt.Errorf("oauth2: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	})
+
+	return r
+}
+
+// Token returns a cached access token or fetches a new one from TokenURL
+func (p *OAuth2ClientCredentials) Token() (string, error) {
+	return p.TokenContext(context.Background())
+}
+
+// TokenContext is like Token but fetches with ctx. The lock is not held
+// while fetching, so a caller whose ctx is done stops waiting right away.
+func (p *OAuth2ClientCredentials) TokenContext(ctx context.Context) (string, error) {
+	for {
+		p.mu.Lock()
+		if p.token != "" && (p.expiry.IsZero() || time.Now().Before(p.expiry)) {
+			token := p.token
+			p.mu.Unlock()
+			return token, nil
+		}
+
+		if wait := p.refreshing; wait != nil {
+			p.mu.Unlock()
+			select {
+			case <-wait:
+				continue
+			case <-ctx.Done():
+				return "", ctx.Err()
+			}
+		}
+
+		done := make(chan struct{})
+		p.refreshing = done
+		p.mu.Unlock()
+
+		token, expiry, err := p.fetch(ctx)
+
+		p.mu.Lock()
+		if err == nil {
+			p.token, p.expiry = token, expiry
+		}
+		p.refreshing = nil
+		p.mu.Unlock()
+		close(done)
+
+		return token, err
+	}
+}
+
+// fetch requests a new access token from TokenURL
+func (p *OAuth2ClientCredentials) fetch(ctx context.Context) (string, time.Time, error) {
+	form := url.Values{"grant_type": {"client_credentials"}}
+	if len(p.Scopes) > 0 {
+		form.Set("scope", strings.Join(p.Scopes, " "))
+	}
+
+	req := Post(p.TokenURL).
+		BasicAuth(url.QueryEscape(p.ClientID), url.QueryEscape(p.ClientSecret)).
+		BodyForm(form)
+	if p.HTTPClient != nil {
+		req = req.Client(p.HTTPClient)
+	}
+
+	resp := req.DoContext(ctx)
+	if err := resp.AsHTTPError(); err != nil {
+		return "", time.Time{}, fmt.Errorf("fetch token: %w", err)
+	}
+
+	var tok struct {
+		AccessToken string `json:"access_token"`
+		ExpiresIn   int64  `json:"expires_in"`
+	}
+	if err := resp.JSON(&tok); err != nil {
+		return "", time.Time{}, fmt.Errorf("fetch token: %w", err)
+	}
+	if tok.AccessToken == "" {
+		return "", time.Time{}, errors.New("fetch token: empty access_token in response")
+	}
+
+	var expiry time.Time
+	if tok.ExpiresIn > 0 {
+		lifetime := time.Duration(tok.ExpiresIn) * time.Second
+		expiry = time.Now().Add(lifetime - min(lifetime/10, oauth2ExpiryDelta))
+	}
+
+	return tok.AccessToken, expiry, nil
+}
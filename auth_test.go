@@ -1,11 +1,17 @@
 package rq
 
 import (
+	"context"
 	"encoding/base64"
+	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
+	"time"
 )
 
 func TestBasicAuth(t *testing.T) {
@@ -96,6 +102,55 @@ func TestCustomAuth(t *testing.T) {
 	}
 }
 
+func TestAPIKeyAuth(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, "%s|%s", r.Header.Get("X-API-Key"), r.URL.Query().Get("api_key"))
+	}))
+	defer srv.Close()
+
+	tests := map[string]struct {
+		provider APIKeyAuth
+		want     string
+		wantErr  bool
+	}{
+		"default header": {
+			provider: APIKeyAuth{Key: "X-API-Key", Value: "k1"},
+			want:     "k1|",
+		},
+		"query": {
+			provider: APIKeyAuth{Key: "api_key", Value: "k2", In: InQuery},
+			want:     "|k2",
+		},
+		"empty value": {
+			provider: APIKeyAuth{Key: "X-API-Key"},
+			wantErr:  true,
+		},
+		"empty key": {
+			provider: APIKeyAuth{Value: "k1"},
+			wantErr:  true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := Get(srv.URL).WithAuth(tt.provider).Do()
+			if tt.wantErr {
+				if resp.Error() == nil {
+					t.Error("want error, got nil")
+				}
+				return
+			}
+			if resp.Error() != nil {
+				t.Fatal(resp.Error())
+			}
+
+			if body, _ := resp.String(); body != tt.want {
+				t.Errorf("want %q, got %q", tt.want, body)
+			}
+		})
+	}
+}
+
 func TestCustomAuthProvider(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Header.Get("X-Custom-Auth") != "custom-value" {
@@ -135,3 +190,146 @@ func (p customAuthProvider) Apply(r *Request) *Request {
 	}
 	return r
 }
+
+func TestOAuth2ClientCredentials(t *testing.T) {
+	var fetches int32
+
+	tokenSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "client" || pass != "secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.FormValue("grant_type") != "client_credentials" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.FormValue("scope") != "read write" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		n := atomic.AddInt32(&fetches, 1)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"access_token":"token-%d","token_type":"Bearer","expires_in":3600}`, n)
+	}))
+	defer tokenSrv.Close()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	provider := &OAuth2ClientCredentials{
+		TokenURL:     tokenSrv.URL,
+		ClientID:     "client",
+		ClientSecret: "secret",
+		Scopes:       []string{"read", "write"},
+	}
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			resp := Get(srv.URL).WithAuth(provider).Do()
+			if resp.Error() != nil {
+				t.Error(resp.Error())
+				return
+			}
+			if resp.StatusCode != http.StatusOK {
+				t.Errorf("want status 200, got %d", resp.StatusCode)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&fetches); got != 1 {
+		t.Errorf("want 1 token fetch, got %d", got)
+	}
+
+	t.Run("refreshes expired token", func(t *testing.T) {
+		provider.mu.Lock()
+		provider.expiry = time.Now().Add(-time.Second)
+		provider.mu.Unlock()
+
+		token, err := provider.Token()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if token != "token-2" {
+			t.Errorf("want token-2, got %s", token)
+		}
+	})
+
+	t.Run("fetch uses request context", func(t *testing.T) {
+		release := make(chan struct{})
+		slow := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			select {
+			case <-r.Context().Done():
+			case <-release:
+			}
+		}))
+		defer slow.Close()
+		defer close(release)
+
+		blocked := &OAuth2ClientCredentials{TokenURL: slow.URL}
+
+		start := time.Now()
+		resp := Get(srv.URL).WithAuth(blocked).Timeout(50 * time.Millisecond).Do()
+		if !errors.Is(resp.Error(), context.DeadlineExceeded) {
+			t.Errorf("want context.DeadlineExceeded, got %v", resp.Error())
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("want fetch to stop with the request timeout, took %v", elapsed)
+		}
+
+		// A fetch in progress must not block callers whose context is done
+		go blocked.Token()
+		time.Sleep(20 * time.Millisecond)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+		if _, err := blocked.TokenContext(ctx); !errors.Is(err, context.DeadlineExceeded) {
+			t.Errorf("want waiting caller to give up with its context, got %v", err)
+		}
+	})
+
+	t.Run("short-lived token is reused", func(t *testing.T) {
+		var shortFetches int32
+		short := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&shortFetches, 1)
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"access_token":"short","expires_in":5}`)
+		}))
+		defer short.Close()
+
+		provider := &OAuth2ClientCredentials{TokenURL: short.URL}
+		for range 3 {
+			if _, err := provider.Token(); err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		if got := atomic.LoadInt32(&shortFetches); got != 1 {
+			t.Errorf("want 1 token fetch, got %d", got)
+		}
+	})
+
+	t.Run("token endpoint error", func(t *testing.T) {
+		bad := &OAuth2ClientCredentials{
+			TokenURL:     tokenSrv.URL,
+			ClientID:     "client",
+			ClientSecret: "wrong",
+		}
+
+		resp := Get(srv.URL).WithAuth(bad).Do()
+		if resp.Error() == nil {
+			t.Error("want error for rejected credentials, got nil")
+		}
+	})
+}
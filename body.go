@@ -3,10 +3,16 @@ package rq
 import (
 	"bytes"
 	"encoding/json"
+	"encoding/xml"
+	"errors"
 	"fmt"
 	"io"
+	"mime"
+	"net/http/httputil"
 	"net/url"
 	"os"
+	"path"
+	"path/filepath"
 	"strings"
 )
 
@@ -21,9 +27,79 @@ func (r *Request) Body(body io.Reader) *Request {
 		return r
 	}
 	r.body = body
+	r.bodyBytes = nil
+	r.hasBodyLength = false
 	return r
 }
 
+// BodyReaderLen creates a new request with a body of n bytes from an io.Reader
+func BodyReaderLen(body io.Reader, n int64) *Request {
+	return New().BodyReaderLen(body, n)
+}
+
+// BodyReaderLen sets the request body from an io.Reader that yields n bytes.
+// The Content-Length header is sent even for readers whose size net/http
+// cannot tell, such as pipes, for servers that reject chunked uploads. A
+// negative n forces chunked transfer encoding instead. The request fails if
+// the reader yields fewer than n bytes.
+func (r *Request) BodyReaderLen(body io.Reader, n int64) *Request {
+	if r.err != nil {
+		return r
+	}
+	r.Body(body)
+	r.bodyLength = n
+	r.hasBodyLength = true
+	return r
+}
+
+// ContentLength returns the length of the request body when it is known:
+// for in-memory bodies, readers whose length can be told, and lengths given
+// with BodyReaderLen. It returns 0 without a body and -1 when unknown.
+func (r *Request) ContentLength() int64 {
+	switch {
+	case r.bodyBytes != nil:
+		return int64(len(r.bodyBytes))
+	case r.hasBodyLength:
+		return max(r.bodyLength, -1)
+	}
+
+	switch body := r.body.(type) {
+	case nil:
+		return 0
+	case *bytes.Reader:
+		return int64(body.Len())
+	case *bytes.Buffer:
+		return int64(body.Len())
+	case *strings.Reader:
+		return int64(body.Len())
+	default:
+		return -1
+	}
+}
+
+// setBodyBytes sets an in-memory body that can be replayed by clones
+func (r *Request) setBodyBytes(data []byte) {
+	r.body = bytes.NewReader(data)
+	r.bodyBytes = data
+	r.hasBodyLength = false
+}
+
+// bufferedBody returns the request body, buffering a reader set with Body
+func (r *Request) bufferedBody() []byte {
+	if r.bodyBytes != nil || r.body == nil {
+		return r.bodyBytes
+	}
+
+	data, err := io.ReadAll(r.body)
+	if err != nil {
+		r.err = fmt.Errorf("failed to read body: %w", err)
+		return nil
+	}
+	r.setBodyBytes(data)
+
+	return data
+}
+
 // BodyString creates a new request with a string body
 func BodyString(body string) *Request {
 	return New().BodyString(body)
@@ -34,7 +110,7 @@ func (r *Request) BodyString(body string) *Request {
 	if r.err != nil {
 		return r
 	}
-	r.body = strings.NewReader(body)
+	r.setBodyBytes([]byte(body))
 	return r
 }
 
@@ -48,7 +124,7 @@ func (r *Request) BodyBytes(body []byte) *Request {
 	if r.err != nil {
 		return r
 	}
-	r.body = bytes.NewReader(body)
+	r.setBodyBytes(body)
 	return r
 }
 
@@ -69,11 +145,90 @@ func (r *Request) BodyJSON(v any) *Request {
 		return r
 	}
 
-	r.body = bytes.NewReader(data)
-	r.headers.Set("Content-Type", "application/json")
+	r.setBodyBytes(data)
+	r.header().Set("Content-Type", "application/json")
+	return r
+}
+
+// BodyAuto creates a new request with a body marshalled as JSON
+func BodyAuto(v any) *Request {
+	return New().BodyAuto(v)
+}
+
+// BodyAuto sets the request body, picking the encoding from the Content-Type
+// header set so far. JSON and XML types, including +json and +xml suffixes,
+// are marshalled accordingly and application/x-www-form-urlencoded accepts
+// url.Values, map[string]string or a struct as for BodyFormStruct. Without a
+// Content-Type the body is marshalled as JSON and the header is set.
+// An io.Reader, []byte or string is always sent as is.
+func (r *Request) BodyAuto(v any) *Request {
+	if r.err != nil {
+		return r
+	}
+
+	switch body := v.(type) {
+	case io.Reader:
+		return r.Body(body)
+	case []byte:
+		return r.BodyBytes(body)
+	case string:
+		return r.BodyString(body)
+	}
+
+	contentType := r.headers.Get("Content-Type")
+	if contentType == "" {
+		return r.BodyJSON(v)
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		r.err = fmt.Errorf("invalid Content-Type %q: %w", contentType, err)
+		return r
+	}
+
+	var data []byte
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		data, err = json.Marshal(v)
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		data, err = xml.Marshal(v)
+	case mediaType == "application/x-www-form-urlencoded":
+		data, err = encodeForm(v)
+	default:
+		r.err = fmt.Errorf("cannot encode %T as %s; pass an io.Reader, []byte or string", v, mediaType)
+		return r
+	}
+	if err != nil {
+		r.err = fmt.Errorf("failed to encode body as %s: %w", mediaType, err)
+		return r
+	}
+
+	r.setBodyBytes(data)
 	return r
 }
 
+// encodeForm encodes url.Values, a string map or a struct as form data
+func encodeForm(v any) ([]byte, error) {
+	switch form := v.(type) {
+	case url.Values:
+		return []byte(form.Encode()), nil
+	case map[string][]string:
+		return []byte(url.Values(form).Encode()), nil
+	case map[string]string:
+		values := make(url.Values, len(form))
+		for k, v := range form {
+			values.Set(k, v)
+		}
+		return []byte(values.Encode()), nil
+	}
+
+	values, err := structValues(v, "form", "url")
+	if err != nil {
+		return nil, err
+	}
+	return []byte(values.Encode()), nil
+}
+
 // BodyForm creates a new request with form data
 func BodyForm(data url.Values) *Request {
 	return New().BodyForm(data)
@@ -85,11 +240,45 @@ func (r *Request) BodyForm(data url.Values) *Request {
 		return r
 	}
 
-	r.body = strings.NewReader(data.Encode())
-	r.headers.Set("Content-Type", "application/x-www-form-urlencoded")
+	r.setBodyBytes([]byte(data.Encode()))
+	r.header().Set("Content-Type", "application/x-www-form-urlencoded")
 	return r
 }
 
+// PostForm creates a new POST request with form data
+func PostForm(urlStr string, data url.Values) *Request {
+	return Post(urlStr).BodyForm(data)
+}
+
+// PostFormStruct creates a new POST request with form data encoded from a struct.
+// Field names are taken from `form` tags, then `url` tags.
+func PostFormStruct(urlStr string, v any) *Request {
+	return Post(urlStr).BodyFormStruct(v)
+}
+
+// BodyFormStruct creates a new request with form data encoded from a struct
+func BodyFormStruct(v any) *Request {
+	return New().BodyFormStruct(v)
+}
+
+// BodyFormStruct sets the request body as form data encoded from the fields
+// of a struct, with the same content type as BodyForm. Field names are taken
+// from `form` tags, then `url` tags, and nested structs are flattened as
+// parent[child]. The other options are the same as for QueryStruct.
+func (r *Request) BodyFormStruct(v any) *Request {
+	if r.err != nil {
+		return r
+	}
+
+	data, err := structValues(v, "form", "url")
+	if err != nil {
+		r.err = fmt.Errorf("failed to encode form: %w", err)
+		return r
+	}
+
+	return r.BodyForm(data)
+}
+
 // Bytes returns the response body as bytes
 func (r *Response) Bytes() ([]byte, error) {
 	if r.err != nil {
@@ -98,6 +287,15 @@ func (r *Response) Bytes() ([]byte, error) {
 	return r.body, nil
 }
 
+// Size returns the length of the buffered response body, or -1 for
+// streamed responses whose body was not read
+func (r *Response) Size() int {
+	if r.stream {
+		return -1
+	}
+	return len(r.body)
+}
+
 // String returns the response body as string
 func (r *Response) String() (string, error) {
 	if r.err != nil {
@@ -106,11 +304,15 @@ func (r *Response) String() (string, error) {
 	return string(r.body), nil
 }
 
-// JSON decodes the response body as JSON
+// JSON decodes the response body as JSON. The empty body of a 304 Not
+// Modified response leaves v unchanged.
 func (r *Response) JSON(v any) error {
 	if r.err != nil {
 		return r.err
 	}
+	if len(r.body) == 0 && r.NotModified() {
+		return nil
+	}
 
 	if err := json.Unmarshal(r.body, v); err != nil {
 		return fmt.Errorf("decode JSON: %w", err)
@@ -119,6 +321,114 @@ func (r *Response) JSON(v any) error {
 	return nil
 }
 
+// XML decodes the response body as XML
+func (r *Response) XML(v any) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	if err := xml.Unmarshal(r.body, v); err != nil {
+		return fmt.Errorf("decode XML: %w", err)
+	}
+
+	return nil
+}
+
+// Decode decodes the response body according to its Content-Type, using
+// JSON for JSON types, including +json suffixes, and XML for XML types.
+// A response without a Content-Type is decoded as JSON; other types are
+// reported as an error.
+func (r *Response) Decode(v any) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	var contentType string
+	if r.Response != nil {
+		contentType = r.Header.Get("Content-Type")
+	}
+	if contentType == "" {
+		return r.JSON(v)
+	}
+
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return fmt.Errorf("invalid Content-Type %q: %w", contentType, err)
+	}
+
+	switch {
+	case mediaType == "application/json" || strings.HasSuffix(mediaType, "+json"):
+		return r.JSON(v)
+	case mediaType == "application/xml" || mediaType == "text/xml" || strings.HasSuffix(mediaType, "+xml"):
+		return r.XML(v)
+	default:
+		return fmt.Errorf("cannot decode response with Content-Type %s", mediaType)
+	}
+}
+
+// JSONMerge deep-merges the JSON response body into v, which must be a
+// non-nil pointer to an existing value such as a map or struct.
+// Objects are merged recursively, so nested keys missing from the response
+// keep their current values. Arrays and scalar values, including null,
+// replace what is already there; arrays are never appended to.
+func (r *Response) JSONMerge(v any) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	src, err := decodeJSONValue(r.body)
+	if err != nil {
+		return fmt.Errorf("decode JSON: %w", err)
+	}
+
+	current, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("encode JSON merge target: %w", err)
+	}
+
+	dst, err := decodeJSONValue(current)
+	if err != nil {
+		return fmt.Errorf("decode JSON merge target: %w", err)
+	}
+
+	merged, err := json.Marshal(mergeJSONValues(dst, src))
+	if err != nil {
+		return fmt.Errorf("encode merged JSON: %w", err)
+	}
+
+	if err := json.Unmarshal(merged, v); err != nil {
+		return fmt.Errorf("decode JSON: %w", err)
+	}
+
+	return nil
+}
+
+// decodeJSONValue decodes data into generic values, keeping numbers exact
+func decodeJSONValue(data []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// mergeJSONValues merges src into dst, recursing into objects present in both
+func mergeJSONValues(dst, src any) any {
+	dstObj, dstOK := dst.(map[string]any)
+	srcObj, srcOK := src.(map[string]any)
+	if !dstOK || !srcOK {
+		return src
+	}
+
+	for k, v := range srcObj {
+		dstObj[k] = mergeJSONValues(dstObj[k], v)
+	}
+	return dstObj
+}
+
 // MustJSON decodes the response body as JSON, panicking on error
 // This is useful for cases where you want fail fast on JSON decode errors
 func (r *Response) MustJSON(v any) {
@@ -127,6 +437,87 @@ func (r *Response) MustJSON(v any) {
 	}
 }
 
+// JSONResponse decodes the response body as JSON into a new T
+func JSONResponse[T any](resp *Response) (T, error) {
+	var v T
+	if err := resp.JSON(&v); err != nil {
+		return v, err
+	}
+	return v, nil
+}
+
+// MustJSONResponse decodes the response body as JSON into a new T, panicking on error
+func MustJSONResponse[T any](resp *Response) T {
+	v, err := JSONResponse[T](resp)
+	if err != nil {
+		panic(err)
+	}
+	return v
+}
+
+// JSONPretty returns the response body re-indented for display
+func (r *Response) JSONPretty() (string, error) {
+	if r.err != nil {
+		return "", r.err
+	}
+
+	var buf bytes.Buffer
+	if err := json.Indent(&buf, r.body, "", "  "); err != nil {
+		return "", fmt.Errorf("indent JSON: %w", err)
+	}
+
+	return buf.String(), nil
+}
+
+// Dump returns the status line, headers and body in HTTP wire format for
+// debugging, e.g. in t.Log. See DumpTo.
+func (r *Response) Dump() string {
+	var sb strings.Builder
+	_ = r.DumpTo(&sb)
+	return sb.String()
+}
+
+// DumpTo writes the status line, headers and body to w in HTTP wire format.
+// The buffered body is used, so nothing is consumed; JSON bodies are
+// indented and binary bodies summarized. Streamed responses are dumped
+// without their body and failed requests print the error.
+func (r *Response) DumpTo(w io.Writer) error {
+	if r.err != nil {
+		_, err := fmt.Fprintf(w, "error: %v\n", r.err)
+		return err
+	}
+	if r.Response == nil {
+		_, err := io.WriteString(w, "error: no response\n")
+		return err
+	}
+
+	body := r.body
+	switch {
+	case r.stream:
+		body = nil
+	case isBinary(body):
+		body = fmt.Appendf(nil, "[%d bytes of binary data]", len(body))
+	case strings.Contains(r.Header.Get("Content-Type"), "json") && json.Valid(body):
+		var buf bytes.Buffer
+		if err := json.Indent(&buf, body, "", "  "); err == nil {
+			body = buf.Bytes()
+		}
+	}
+
+	dumpResp := *r.Response
+	dumpResp.Body = io.NopCloser(bytes.NewReader(body))
+	dumpResp.ContentLength = int64(len(body))
+	dumpResp.TransferEncoding = nil
+
+	dump, err := httputil.DumpResponse(&dumpResp, !r.stream)
+	if err != nil {
+		return fmt.Errorf("dump response: %w", err)
+	}
+
+	_, err = w.Write(dump)
+	return err
+}
+
 // BodyReader return an io.Reader for the response body
 func (r *Response) BodyReader() (io.Reader, error) {
 	if r.err != nil {
@@ -135,6 +526,34 @@ func (r *Response) BodyReader() (io.Reader, error) {
 	return bytes.NewReader(r.body), nil
 }
 
+// maxDiscardBytes caps how much of an unread body Discard drains
+const maxDiscardBytes = 256 << 10
+
+// Discard drains and closes an unread response body so the connection can be
+// reused. At most 256KB is drained; larger bodies just get closed.
+// For buffered responses, whose body is already read and closed, it is a no-op.
+func (r *Response) Discard() error {
+	if !r.stream || r.Response == nil || r.Response.Body == nil {
+		return nil
+	}
+
+	_, err := io.Copy(io.Discard, io.LimitReader(r.Response.Body, maxDiscardBytes))
+	if closeErr := r.Response.Body.Close(); err == nil {
+		err = closeErr
+	}
+	r.stream = false
+
+	return err
+}
+
+// bodyReader returns a reader over the buffered or streamed body
+func (r *Response) bodyReader() io.Reader {
+	if r.stream && r.Response != nil && r.Response.Body != nil {
+		return r.Response.Body
+	}
+	return bytes.NewReader(r.body)
+}
+
 // SaveToFile saves the response body to a file
 func (r *Response) SaveToFile(filename string) error {
 	if r.err != nil {
@@ -143,3 +562,57 @@ func (r *Response) SaveToFile(filename string) error {
 
 	return os.WriteFile(filename, r.body, 0o600)
 }
+
+// defaultDownloadName is used by SaveToDir when no usable filename is found
+const defaultDownloadName = "download"
+
+// SaveToDir saves the response body into dir and returns the file path.
+// The filename comes from the Content-Disposition header, falling back to
+// the last segment of the final URL path. Directory components are
+// stripped so the server cannot write outside dir.
+func (r *Response) SaveToDir(dir string) (string, error) {
+	if r.err != nil {
+		return "", r.err
+	}
+	if r.Response == nil {
+		return "", errors.New("save to dir: no response")
+	}
+
+	var name string
+	if _, params, err := mime.ParseMediaType(r.Header.Get("Content-Disposition")); err == nil {
+		name = params["filename"]
+	}
+	if sanitizeFilename(name) == "" && r.Request != nil && r.Request.URL != nil {
+		name = r.Request.URL.Path
+	}
+	name = sanitizeFilename(name)
+	if name == "" {
+		name = defaultDownloadName
+	}
+
+	dest := filepath.Join(dir, name)
+	if err := os.WriteFile(dest, r.body, 0o600); err != nil {
+		return "", err
+	}
+
+	return dest, nil
+}
+
+// sanitizeFilename reduces name to a plain file name, or "" if nothing
+// usable is left
+func sanitizeFilename(name string) string {
+	name = strings.ReplaceAll(name, "\\", "/")
+	name = strings.Map(func(c rune) rune {
+		if c < 0x20 || c == 0x7f {
+			return -1
+		}
+		return c
+	}, name)
+
+	name = strings.TrimSpace(path.Base(name))
+	switch name {
+	case ".", "..", "/":
+		return ""
+	}
+	return name
+}
@@ -2,12 +2,16 @@ package rq
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"fmt"
 	"io"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 )
@@ -107,6 +111,115 @@ func TestBodyForm(t *testing.T) {
 	}
 }
 
+func TestPostForm(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("want method POST, got %s", r.Method)
+		}
+		if r.Header.Get("Content-Type") != "application/x-www-form-urlencoded" {
+			t.Errorf("want Content-Type application/x-www-form-urlencoded, got %s", r.Header.Get("Content-Type"))
+		}
+
+		if err := r.ParseForm(); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+		json.NewEncoder(w).Encode(r.PostForm)
+	}))
+	defer srv.Close()
+
+	t.Run("url.Values", func(t *testing.T) {
+		resp := PostForm(srv.URL, url.Values{"grant_type": {"password"}}).Do()
+
+		var result map[string][]string
+		if err := resp.JSON(&result); err != nil {
+			t.Fatal(err)
+		}
+		if got := result["grant_type"]; len(got) != 1 || got[0] != "password" {
+			t.Errorf("want grant_type=password, got %v", got)
+		}
+	})
+
+	t.Run("struct", func(t *testing.T) {
+		type login struct {
+			Username string   `form:"username"`
+			Password string   `url:"password"`
+			Remember bool     `form:"remember,omitempty"`
+			Scopes   []string `form:"scope"`
+			Ignored  string   `form:"-"`
+		}
+
+		resp := PostFormStruct(srv.URL, login{
+			Username: "testuser",
+			Password: "testpass",
+			Scopes:   []string{"read", "write"},
+			Ignored:  "x",
+		}).Do()
+
+		var result map[string][]string
+		if err := resp.JSON(&result); err != nil {
+			t.Fatal(err)
+		}
+
+		want := map[string][]string{
+			"username": {"testuser"},
+			"password": {"testpass"},
+			"scope":    {"read", "write"},
+		}
+		if len(result) != len(want) {
+			t.Errorf("want %v, got %v", want, result)
+		}
+		for k, v := range want {
+			if strings.Join(result[k], ",") != strings.Join(v, ",") {
+				t.Errorf("want form field %s=%v, got %v", k, v, result[k])
+			}
+		}
+	})
+
+	t.Run("nested struct", func(t *testing.T) {
+		type address struct {
+			City string `form:"city"`
+			Zip  string `form:"zip,omitempty"`
+		}
+		type signup struct {
+			Name    string  `form:"name"`
+			Address address `form:"address"`
+		}
+
+		resp := Post(srv.URL).BodyFormStruct(signup{
+			Name:    "john",
+			Address: address{City: "Berlin"},
+		}).Do()
+
+		var result map[string][]string
+		if err := resp.JSON(&result); err != nil {
+			t.Fatal(err)
+		}
+
+		want := map[string][]string{
+			"name":          {"john"},
+			"address[city]": {"Berlin"},
+		}
+		if len(result) != len(want) {
+			t.Errorf("want %v, got %v", want, result)
+		}
+		for k, v := range want {
+			if strings.Join(result[k], ",") != strings.Join(v, ",") {
+				t.Errorf("want form field %s=%v, got %v", k, v, result[k])
+			}
+		}
+	})
+
+	t.Run("non-struct", func(t *testing.T) {
+		resp := PostFormStruct(srv.URL, "not a struct").Do()
+		if resp.Error() == nil {
+			t.Error("want error for non-struct value, got nil")
+		}
+	})
+}
+
 func TestBodyReader(t *testing.T) {
 	wantBody := "Hello!"
 
@@ -139,6 +252,70 @@ func TestBodyReader(t *testing.T) {
 	}
 }
 
+func TestBodyReaderLen(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		fmt.Fprintf(w, "%d|%v|%s", r.ContentLength, r.TransferEncoding, body)
+	}))
+	defer srv.Close()
+
+	// pipe hides the reader type so net/http cannot size it
+	pipe := func(s string) io.Reader {
+		pr, pw := io.Pipe()
+		go func() {
+			pw.Write([]byte(s))
+			pw.Close()
+		}()
+		return pr
+	}
+
+	tests := map[string]struct {
+		req     *Request
+		want    string
+		wantErr bool
+	}{
+		"unsized reader is chunked": {
+			req:  Post(srv.URL).Body(pipe("hello")),
+			want: "-1|[chunked]|hello",
+		},
+		"known length": {
+			req:  Post(srv.URL).BodyReaderLen(pipe("hello"), 5),
+			want: "5|[]|hello",
+		},
+		"forced chunked": {
+			req:  Post(srv.URL).BodyReaderLen(strings.NewReader("hello"), -1),
+			want: "-1|[chunked]|hello",
+		},
+		"empty": {
+			req:  BodyReaderLen(io.MultiReader(), 0).Method(http.MethodPut).URL(srv.URL),
+			want: "0|[]|",
+		},
+		"short reader": {
+			req:     Post(srv.URL).BodyReaderLen(pipe("hi"), 5),
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := tt.req.Do()
+			if tt.wantErr {
+				if resp.Error() == nil {
+					t.Error("want error, got nil")
+				}
+				return
+			}
+			if resp.Error() != nil {
+				t.Fatal(resp.Error())
+			}
+
+			if body, _ := resp.String(); body != tt.want {
+				t.Errorf("want %q, got %q", tt.want, body)
+			}
+		})
+	}
+}
+
 func TestBodyJSON(t *testing.T) {
 	type TestUser struct {
 		ID       int            `json:"id"`
@@ -341,3 +518,614 @@ func TestMustJSON(t *testing.T) {
 		}
 	})
 }
+
+func TestJSONResponse(t *testing.T) {
+	type user struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/user":
+			w.Write([]byte(`{"id": 1, "name": "John"}`))
+		case "/list":
+			w.Write([]byte(`[1, 2, 3]`))
+		default:
+			w.Write([]byte(`{"id":`))
+		}
+	}))
+	defer srv.Close()
+
+	t.Run("struct", func(t *testing.T) {
+		got, err := JSONResponse[user](Get(srv.URL + "/user").Do())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != (user{ID: 1, Name: "John"}) {
+			t.Errorf("want {1 John}, got %+v", got)
+		}
+	})
+
+	t.Run("slice", func(t *testing.T) {
+		got := MustJSONResponse[[]int](Get(srv.URL + "/list").Do())
+		if len(got) != 3 {
+			t.Errorf("want 3 items, got %v", got)
+		}
+	})
+
+	t.Run("malformed", func(t *testing.T) {
+		if _, err := JSONResponse[user](Get(srv.URL + "/bad").Do()); err == nil {
+			t.Error("want decode error, got nil")
+		}
+	})
+
+	t.Run("request error", func(t *testing.T) {
+		resp := New().Do()
+		if _, err := JSONResponse[user](resp); !errors.Is(err, resp.Error()) {
+			t.Errorf("want request error, got %v", err)
+		}
+	})
+
+	t.Run("must panics", func(t *testing.T) {
+		defer func() {
+			if recover() == nil {
+				t.Error("want panic, got none")
+			}
+		}()
+		MustJSONResponse[user](Get(srv.URL + "/bad").Do())
+	})
+}
+
+func TestJSONPretty(t *testing.T) {
+	t.Run("indents valid JSON", func(t *testing.T) {
+		resp := &Response{body: []byte(`{"name":"John","tags":["a","b"]}`)}
+
+		got, err := resp.JSONPretty()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := "{\n  \"name\": \"John\",\n  \"tags\": [\n    \"a\",\n    \"b\"\n  ]\n}"
+		if got != want {
+			t.Errorf("want %q, got %q", want, got)
+		}
+	})
+
+	t.Run("errors on invalid JSON", func(t *testing.T) {
+		resp := &Response{body: []byte(`not json`)}
+
+		if _, err := resp.JSONPretty(); err == nil {
+			t.Error("want error for invalid JSON, got nil")
+		}
+	})
+
+	t.Run("returns response error", func(t *testing.T) {
+		wantErr := errors.New("network error")
+		resp := &Response{err: wantErr}
+
+		if _, err := resp.JSONPretty(); !errors.Is(err, wantErr) {
+			t.Errorf("want %v, got %v", wantErr, err)
+		}
+	})
+}
+
+type trackingBody struct {
+	io.Reader
+	closed bool
+}
+
+func (b *trackingBody) Close() error {
+	b.closed = true
+	return nil
+}
+
+func TestResponseDump(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/json":
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"id":1}`))
+		case "/binary":
+			w.Write([]byte{0x00, 0x01, 0xff})
+		default:
+			w.Header().Set("X-Trace", "abc")
+			w.WriteHeader(http.StatusTeapot)
+			w.Write([]byte("short and stout"))
+		}
+	}))
+	defer srv.Close()
+
+	tests := map[string]struct {
+		resp *Response
+		want []string
+	}{
+		"text": {
+			resp: Get(srv.URL).Do(),
+			want: []string{"HTTP/1.1 418 I'm a teapot\r\n", "X-Trace: abc\r\n", "Content-Length: 15\r\n", "\r\n\r\nshort and stout"},
+		},
+		"json": {
+			resp: Get(srv.URL + "/json").Do(),
+			want: []string{"HTTP/1.1 200 OK", "{\n  \"id\": 1\n}"},
+		},
+		"binary": {
+			resp: Get(srv.URL + "/binary").Do(),
+			want: []string{"[3 bytes of binary data]"},
+		},
+		"failed request": {
+			resp: New().Do(),
+			want: []string{"error: "},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dump := tt.resp.Dump()
+			for _, want := range tt.want {
+				if !strings.Contains(dump, want) {
+					t.Errorf("want dump to contain %q, got:\n%s", want, dump)
+				}
+			}
+		})
+	}
+
+	t.Run("body still readable", func(t *testing.T) {
+		resp := Get(srv.URL).Do()
+
+		var buf bytes.Buffer
+		if err := resp.DumpTo(&buf); err != nil {
+			t.Fatal(err)
+		}
+		if body, _ := resp.String(); body != "short and stout" {
+			t.Errorf("want body to be unchanged, got %q", body)
+		}
+	})
+}
+
+func TestDiscard(t *testing.T) {
+	t.Run("drains and closes streamed body", func(t *testing.T) {
+		reader := strings.NewReader("unread body")
+		body := &trackingBody{Reader: reader}
+		resp := &Response{
+			Response: &http.Response{Body: body},
+			stream:   true,
+		}
+
+		if err := resp.Discard(); err != nil {
+			t.Fatal(err)
+		}
+		if !body.closed {
+			t.Error("want body to be closed")
+		}
+		if reader.Len() != 0 {
+			t.Errorf("want body to be drained, %d bytes left", reader.Len())
+		}
+	})
+
+	t.Run("caps drained bytes", func(t *testing.T) {
+		reader := bytes.NewReader(make([]byte, maxDiscardBytes+100))
+		body := &trackingBody{Reader: reader}
+		resp := &Response{
+			Response: &http.Response{Body: body},
+			stream:   true,
+		}
+
+		if err := resp.Discard(); err != nil {
+			t.Fatal(err)
+		}
+		if !body.closed {
+			t.Error("want body to be closed")
+		}
+		if reader.Len() != 100 {
+			t.Errorf("want 100 bytes left undrained, got %d", reader.Len())
+		}
+	})
+
+	t.Run("no-op for buffered response", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("buffered"))
+		}))
+		defer srv.Close()
+
+		resp := Get(srv.URL).Do()
+		if err := resp.Discard(); err != nil {
+			t.Fatal(err)
+		}
+
+		body, err := resp.String()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if body != "buffered" {
+			t.Errorf("want body to remain available, got %q", body)
+		}
+	})
+}
+
+func TestJSONMerge(t *testing.T) {
+	body := []byte(`{"name": "api", "limits": {"rate": 20}, "tags": ["b"], "owner": null}`)
+
+	t.Run("map", func(t *testing.T) {
+		resp := &Response{body: body}
+		got := map[string]any{
+			"name":   "default",
+			"limits": map[string]any{"rate": 10, "burst": 5},
+			"tags":   []any{"a"},
+			"owner":  "ops",
+			"region": "eu",
+		}
+
+		if err := resp.JSONMerge(&got); err != nil {
+			t.Fatal(err)
+		}
+
+		want := `{"limits":{"burst":5,"rate":20},"name":"api","owner":null,"region":"eu","tags":["b"]}`
+		data, _ := json.Marshal(got)
+		if string(data) != want {
+			t.Errorf("want %s, got %s", want, data)
+		}
+	})
+
+	t.Run("struct", func(t *testing.T) {
+		type Limits struct {
+			Rate  int `json:"rate"`
+			Burst int `json:"burst"`
+		}
+		type Config struct {
+			Name   string            `json:"name"`
+			Limits Limits            `json:"limits"`
+			Tags   []string          `json:"tags"`
+			Extra  map[string]string `json:"extra"`
+		}
+
+		resp := &Response{body: []byte(`{"limits": {"rate": 20}, "tags": ["b"], "extra": {"k2": "v2"}}`)}
+		got := Config{
+			Name:   "default",
+			Limits: Limits{Rate: 10, Burst: 5},
+			Tags:   []string{"a"},
+			Extra:  map[string]string{"k1": "v1"},
+		}
+
+		if err := resp.JSONMerge(&got); err != nil {
+			t.Fatal(err)
+		}
+
+		if got.Name != "default" {
+			t.Errorf("want name to be kept, got %q", got.Name)
+		}
+		if got.Limits != (Limits{Rate: 20, Burst: 5}) {
+			t.Errorf("want limits {20 5}, got %+v", got.Limits)
+		}
+		if len(got.Tags) != 1 || got.Tags[0] != "b" {
+			t.Errorf("want tags to be replaced with [b], got %v", got.Tags)
+		}
+		if got.Extra["k1"] != "v1" || got.Extra["k2"] != "v2" {
+			t.Errorf("want extra to be merged, got %v", got.Extra)
+		}
+	})
+
+	t.Run("invalid JSON", func(t *testing.T) {
+		resp := &Response{body: []byte(`{"name":`)}
+		var got map[string]any
+		if err := resp.JSONMerge(&got); err == nil {
+			t.Error("want error for invalid JSON, got nil")
+		}
+	})
+}
+
+func TestBodyAuto(t *testing.T) {
+	type item struct {
+		XMLName struct{} `json:"-" xml:"item"`
+		Name    string   `json:"name" xml:"name" form:"name"`
+	}
+
+	tests := map[string]struct {
+		contentType     string
+		body            any
+		wantBody        string
+		wantContentType string
+		wantErr         bool
+	}{
+		"default json": {
+			body:            item{Name: "a"},
+			wantBody:        `{"name":"a"}`,
+			wantContentType: "application/json",
+		},
+		"json with charset": {
+			contentType:     "application/json; charset=utf-8",
+			body:            item{Name: "a"},
+			wantBody:        `{"name":"a"}`,
+			wantContentType: "application/json; charset=utf-8",
+		},
+		"vendor json": {
+			contentType:     "application/vnd.api+json",
+			body:            map[string]int{"id": 1},
+			wantBody:        `{"id":1}`,
+			wantContentType: "application/vnd.api+json",
+		},
+		"xml": {
+			contentType:     "application/xml",
+			body:            item{Name: "a"},
+			wantBody:        `<item><name>a</name></item>`,
+			wantContentType: "application/xml",
+		},
+		"form struct": {
+			contentType:     "application/x-www-form-urlencoded",
+			body:            item{Name: "a b"},
+			wantBody:        `name=a+b`,
+			wantContentType: "application/x-www-form-urlencoded",
+		},
+		"form map": {
+			contentType:     "application/x-www-form-urlencoded",
+			body:            map[string]string{"k": "v"},
+			wantBody:        `k=v`,
+			wantContentType: "application/x-www-form-urlencoded",
+		},
+		"raw string with unknown type": {
+			contentType:     "text/csv",
+			body:            "a,b\n",
+			wantBody:        "a,b\n",
+			wantContentType: "text/csv",
+		},
+		"unknown type": {
+			contentType: "application/octet-stream",
+			body:        item{Name: "a"},
+			wantErr:     true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := Post("http://example.com")
+			if tt.contentType != "" {
+				r.Header("Content-Type", tt.contentType)
+			}
+			r.BodyAuto(tt.body)
+
+			if tt.wantErr {
+				if r.err == nil {
+					t.Error("want error, got nil")
+				}
+				return
+			}
+			if r.err != nil {
+				t.Fatal(r.err)
+			}
+
+			if got := string(r.bodyBytes); got != tt.wantBody {
+				t.Errorf("want body %q, got %q", tt.wantBody, got)
+			}
+			if got := r.headers.Get("Content-Type"); got != tt.wantContentType {
+				t.Errorf("want Content-Type %q, got %q", tt.wantContentType, got)
+			}
+		})
+	}
+}
+
+func TestAcceptNegotiation(t *testing.T) {
+	type item struct {
+		Name string `json:"name" xml:"name"`
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.Header.Get("Accept"), "application/xml") {
+			w.Header().Set("Content-Type", "application/xml")
+			w.Write([]byte(`<item><name>xml</name></item>`))
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"name":"json"}`))
+	}))
+	defer srv.Close()
+
+	tests := map[string]struct {
+		types      []string
+		wantHeader string
+		wantName   string
+	}{
+		"json preferred": {
+			types:      []string{"application/json", "application/xml"},
+			wantHeader: "application/json, application/xml;q=0.9",
+			wantName:   "json",
+		},
+		"xml preferred": {
+			types:      []string{"application/xml", "application/json", "text/plain"},
+			wantHeader: "application/xml, application/json;q=0.9, text/plain;q=0.8",
+			wantName:   "xml",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			r := Get(srv.URL).Accept(tt.types...)
+			if got := r.headers.Get("Accept"); got != tt.wantHeader {
+				t.Errorf("want Accept %q, got %q", tt.wantHeader, got)
+			}
+
+			var v item
+			if err := r.Do().Decode(&v); err != nil {
+				t.Fatal(err)
+			}
+			if v.Name != tt.wantName {
+				t.Errorf("want name %q, got %q", tt.wantName, v.Name)
+			}
+		})
+	}
+
+	t.Run("quality floor", func(t *testing.T) {
+		types := make([]string, 12)
+		for i := range types {
+			types[i] = fmt.Sprintf("type/t%d", i)
+		}
+		got := Accept(types...).headers.Get("Accept")
+		if !strings.HasSuffix(got, "type/t10;q=0.1, type/t11;q=0.1") {
+			t.Errorf("want quality to stop at 0.1, got %q", got)
+		}
+	})
+}
+
+func TestDecode(t *testing.T) {
+	type item struct {
+		Name string `json:"name" xml:"name"`
+	}
+
+	tests := map[string]struct {
+		contentType string
+		body        string
+		want        string
+		wantErr     bool
+	}{
+		"json": {
+			contentType: "application/json",
+			body:        `{"name":"json"}`,
+			want:        "json",
+		},
+		"json with charset": {
+			contentType: "application/json; charset=utf-8",
+			body:        `{"name":"charset"}`,
+			want:        "charset",
+		},
+		"problem json": {
+			contentType: "application/problem+json",
+			body:        `{"name":"problem"}`,
+			want:        "problem",
+		},
+		"xml": {
+			contentType: "text/xml; charset=utf-8",
+			body:        `<item><name>xml</name></item>`,
+			want:        "xml",
+		},
+		"no content type": {
+			body: `{"name":"fallback"}`,
+			want: "fallback",
+		},
+		"unsupported": {
+			contentType: "text/html",
+			body:        `<html></html>`,
+			wantErr:     true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			header := make(http.Header)
+			if tt.contentType != "" {
+				header.Set("Content-Type", tt.contentType)
+			}
+			resp := &Response{Response: &http.Response{Header: header}, body: []byte(tt.body)}
+
+			var got item
+			err := resp.Decode(&got)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), tt.contentType) {
+					t.Errorf("want error naming %q, got %v", tt.contentType, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got.Name != tt.want {
+				t.Errorf("want name %q, got %q", tt.want, got.Name)
+			}
+		})
+	}
+}
+
+func TestSaveToDir(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if cd := r.URL.Query().Get("cd"); cd != "" {
+			w.Header().Set("Content-Disposition", cd)
+		}
+		w.Write([]byte("file content"))
+	}))
+	defer srv.Close()
+
+	tests := map[string]struct {
+		path string
+		cd   string
+		want string
+	}{
+		"content disposition": {path: "/files/1", cd: `attachment; filename="report.pdf"`, want: "report.pdf"},
+		"extended filename":   {path: "/files/1", cd: `attachment; filename*=UTF-8''na%C3%AFve.txt`, want: "naïve.txt"},
+		"url fallback":        {path: "/files/data.csv", want: "data.csv"},
+		"traversal":           {path: "/files/1", cd: `attachment; filename="../../etc/passwd"`, want: "passwd"},
+		"absolute path":       {path: "/files/1", cd: `attachment; filename="/tmp/evil.sh"`, want: "evil.sh"},
+		"windows separators":  {path: "/files/1", cd: `attachment; filename="..\\..\\evil.bat"`, want: "evil.bat"},
+		"dot dot falls back":  {path: "/files/archive.zip", cd: `attachment; filename=".."`, want: "archive.zip"},
+		"no usable name":      {path: "/", want: "download"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+
+			path, err := Get(srv.URL+tt.path).QueryParam("cd", tt.cd).Do().SaveToDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if want := filepath.Join(dir, tt.want); path != want {
+				t.Errorf("want path %q, got %q", want, path)
+			}
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(data) != "file content" {
+				t.Errorf("want file content, got %q", data)
+			}
+		})
+	}
+}
+
+func TestBodySizes(t *testing.T) {
+	t.Run("request", func(t *testing.T) {
+		tests := map[string]struct {
+			req  *Request
+			want int64
+		}{
+			"no body":        {req: New(), want: 0},
+			"string":         {req: BodyString("hello"), want: 5},
+			"bytes":          {req: BodyBytes([]byte{1, 2, 3}), want: 3},
+			"json":           {req: BodyJSON(map[string]int{"a": 1}), want: 7},
+			"sized reader":   {req: Body(strings.NewReader("abcd")), want: 4},
+			"unsized reader": {req: Body(io.MultiReader(strings.NewReader("abcd"))), want: -1},
+			"explicit":       {req: BodyReaderLen(io.MultiReader(), 42), want: 42},
+			"forced chunked": {req: BodyReaderLen(strings.NewReader("abcd"), -1), want: -1},
+		}
+
+		for name, tt := range tests {
+			t.Run(name, func(t *testing.T) {
+				if got := tt.req.ContentLength(); got != tt.want {
+					t.Errorf("want %d, got %d", tt.want, got)
+				}
+			})
+		}
+	})
+
+	t.Run("response", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte("hello world"))
+		}))
+		defer srv.Close()
+
+		if got := Get(srv.URL).Do().Size(); got != 11 {
+			t.Errorf("want size 11, got %d", got)
+		}
+
+		var streamed int
+		resp, err := Get(srv.URL).OnResponse(func(r *Response) { streamed = r.Size() }).DoStream(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if streamed != -1 {
+			t.Errorf("want size -1 for streamed response, got %d", streamed)
+		}
+
+		if got := New().Do().Size(); got != 0 {
+			t.Errorf("want size 0 for failed request, got %d", got)
+		}
+	})
+}
@@ -0,0 +1,360 @@
+package rq
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cacheRevalidateTTL is how long an entry with an ETag is kept past its
+// freshness so it can still be revalidated
+const cacheRevalidateTTL = 24 * time.Hour
+
+// memoryCacheSweepMin is the entry count at which MemoryCache first drops
+// expired entries on Set
+const memoryCacheSweepMin = 64
+
+// CacheStore stores responses for Request.Cache
+type CacheStore interface {
+	Get(key string) (*Response, bool)
+	Set(key string, resp *Response, ttl time.Duration)
+}
+
+// Cache creates a new request with a response cache
+func Cache(store CacheStore) *Request {
+	return New().Cache(store)
+}
+
+// Cache enables client-side caching of GET and HEAD responses.
+// Fresh entries, as determined by Cache-Control max-age or Expires, are
+// served without a network call. Stale entries carrying an ETag are
+// revalidated with If-None-Match, and the cached body is reused on a 304;
+// they are kept for a day past their freshness. Entries are only used for
+// requests that match on the headers named by the response's Vary header.
+// The no-store and no-cache directives are honored on both the request
+// and the response, and requests carrying an Authorization header are
+// never cached.
+func (r *Request) Cache(store CacheStore) *Request {
+	if r.err != nil {
+		return r
+	}
+	r.cache = store
+	return r
+}
+
+// IfNoneMatch creates a new request conditional on the resource's ETag
+func IfNoneMatch(etag string) *Request {
+	return New().IfNoneMatch(etag)
+}
+
+// IfNoneMatch sets the If-None-Match header so the server answers 304 Not
+// Modified while the resource still has the given ETag. Unquoted tags are
+// quoted; weak tags and "*" are sent as given.
+func (r *Request) IfNoneMatch(etag string) *Request {
+	if r.err != nil {
+		return r
+	}
+	if etag != "*" && !strings.HasPrefix(etag, "W/") && !strings.HasPrefix(etag, `"`) {
+		etag = `"` + etag + `"`
+	}
+	r.header().Set("If-None-Match", etag)
+	return r
+}
+
+// IfModifiedSince creates a new request conditional on the modification time
+func IfModifiedSince(t time.Time) *Request {
+	return New().IfModifiedSince(t)
+}
+
+// IfModifiedSince sets the If-Modified-Since header so the server answers
+// 304 Not Modified if the resource has not changed since t
+func (r *Request) IfModifiedSince(t time.Time) *Request {
+	if r.err != nil {
+		return r
+	}
+	r.header().Set("If-Modified-Since", t.UTC().Format(http.TimeFormat))
+	return r
+}
+
+// NotModified returns true if status code is 304, the answer to a
+// conditional request for an unchanged resource
+func (r *Response) NotModified() bool {
+	if r.err != nil || r.Response == nil {
+		return false
+	}
+	return r.StatusCode == http.StatusNotModified
+}
+
+type memoryCacheEntry struct {
+	resp    *Response
+	expires time.Time
+}
+
+// MemoryCache is an in-memory CacheStore safe for concurrent use
+type MemoryCache struct {
+	mu      sync.RWMutex
+	entries map[string]memoryCacheEntry
+
+	// sweepAt is the entry count that triggers dropping expired entries
+	sweepAt int
+}
+
+// NewMemoryCache creates an empty MemoryCache
+func NewMemoryCache() *MemoryCache {
+	return &MemoryCache{
+		entries: make(map[string]memoryCacheEntry),
+	}
+}
+
+// Get returns the entry for key if it has not expired
+func (c *MemoryCache) Get(key string) (*Response, bool) {
+	c.mu.RLock()
+	entry, ok := c.entries[key]
+	c.mu.RUnlock()
+
+	if !ok {
+		return nil, false
+	}
+
+	if !entry.expires.IsZero() && time.Now().After(entry.expires) {
+		c.mu.Lock()
+		delete(c.entries, key)
+		c.mu.Unlock()
+		return nil, false
+	}
+
+	return entry.resp, true
+}
+
+// Set stores resp under key for ttl. A ttl <= 0 keeps the entry until it is replaced.
+// Expired entries are dropped as the cache grows.
+func (c *MemoryCache) Set(key string, resp *Response, ttl time.Duration) {
+	now := time.Now()
+	entry := memoryCacheEntry{resp: resp}
+	if ttl > 0 {
+		entry.expires = now.Add(ttl)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if len(c.entries) >= c.sweepAt {
+		for k, e := range c.entries {
+			if !e.expires.IsZero() && now.After(e.expires) {
+				delete(c.entries, k)
+			}
+		}
+		c.sweepAt = max(2*len(c.entries), memoryCacheSweepMin)
+	}
+	c.entries[key] = entry
+}
+
+// updateCache stores a fresh response, or resolves a 304 against the cached one
+func (r *Request) updateCache(key string, cached, response *Response) *Response {
+	if cached != nil && response.StatusCode == http.StatusNotModified {
+		entry := cached.cachedCopy()
+		if lifetime, ok := freshnessLifetime(response.Header); ok {
+			entry.cacheExpires = time.Now().Add(lifetime)
+		} else if lifetime, ok := freshnessLifetime(cached.Header); ok {
+			entry.cacheExpires = time.Now().Add(lifetime)
+		}
+		r.storeResponse(key, entry)
+
+		revalidated := entry.cachedCopy()
+		revalidated.timing = response.timing
+		return revalidated
+	}
+
+	if response.StatusCode != http.StatusOK {
+		return response
+	}
+
+	lifetime, ok := freshnessLifetime(response.Header)
+	if !ok && response.Header.Get("ETag") == "" {
+		return response
+	}
+
+	entry := response.cachedCopy()
+	entry.cacheExpires = time.Now().Add(lifetime)
+	r.storeResponse(key, entry)
+
+	return response
+}
+
+// storeResponse saves entry, keeping revalidatable entries past their freshness
+func (r *Request) storeResponse(key string, entry *Response) {
+	if hasCacheDirective(entry.Header, "no-store") || slices.Contains(varyHeaders(entry.Header), "*") {
+		return
+	}
+	if entry.Request != nil && entry.Request.Header.Get("Authorization") != "" {
+		return
+	}
+
+	ttl := time.Until(entry.cacheExpires)
+	if entry.Header.Get("ETag") != "" {
+		ttl = max(ttl, 0) + cacheRevalidateTTL
+	}
+
+	r.cache.Set(key, entry, ttl)
+}
+
+// varyMatches reports whether a cached response can be used for a request
+// with header h, comparing the headers named by its Vary header against the
+// request it was stored for
+func (r *Response) varyMatches(h http.Header) bool {
+	for _, name := range varyHeaders(r.Header) {
+		if name == "*" || r.Request == nil {
+			return false
+		}
+		if !slices.Equal(r.Request.Header.Values(name), h.Values(name)) {
+			return false
+		}
+	}
+	return true
+}
+
+// varyHeaders returns the header names listed in the Vary header
+func varyHeaders(h http.Header) []string {
+	var names []string
+	for _, line := range h.Values("Vary") {
+		for _, name := range strings.Split(line, ",") {
+			if name = strings.TrimSpace(name); name != "" {
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// isFresh reports whether a cached response can be served without revalidation
+func (r *Response) isFresh() bool {
+	return r.Response != nil && time.Now().Before(r.cacheExpires)
+}
+
+// ReceivedAt returns when the response headers were received. Responses
+// served from the cache keep the time they were originally received.
+func (r *Response) ReceivedAt() time.Time {
+	return r.receivedAt
+}
+
+// Age estimates how long ago the server generated the response, following
+// RFC 9111: the larger of the Age header and the delay since the Date
+// header, plus the time elapsed since the response was received.
+func (r *Response) Age() (time.Duration, error) {
+	if r.Response == nil {
+		return 0, errors.New("age: no response")
+	}
+
+	ageHeader, dateHeader := r.Header.Get("Age"), r.Header.Get("Date")
+	if ageHeader == "" && dateHeader == "" {
+		return 0, errors.New("age: no Date or Age header")
+	}
+
+	received := r.receivedAt
+	if received.IsZero() {
+		received = time.Now()
+	}
+
+	var age time.Duration
+	if ageHeader != "" {
+		seconds, err := strconv.ParseInt(ageHeader, 10, 64)
+		if err != nil || seconds < 0 {
+			return 0, fmt.Errorf("age: invalid Age header %q", ageHeader)
+		}
+		age = time.Duration(seconds) * time.Second
+	}
+	if dateHeader != "" {
+		date, err := http.ParseTime(dateHeader)
+		if err != nil {
+			return 0, fmt.Errorf("age: invalid Date header: %w", err)
+		}
+		age = max(age, received.Sub(date))
+	}
+
+	return age + time.Since(received), nil
+}
+
+// cachedCopy returns a copy that can be handed out and modified independently
+func (r *Response) cachedCopy() *Response {
+	cp := *r
+	cp.err = nil
+	cp.body = slices.Clone(r.body)
+	if r.Response != nil {
+		resp := *r.Response
+		resp.Header = r.Header.Clone()
+		resp.Trailer = r.Response.Trailer.Clone()
+		cp.Response = &resp
+	}
+	return &cp
+}
+
+// freshnessLifetime computes how long a response is fresh from its headers.
+// ok is false when the response is not cacheable or carries no freshness info.
+func freshnessLifetime(h http.Header) (time.Duration, bool) {
+	directives := parseCacheControl(h)
+
+	if _, ok := directives["no-store"]; ok {
+		return 0, false
+	}
+	if _, ok := directives["no-cache"]; ok {
+		return 0, true
+	}
+
+	if v, ok := directives["max-age"]; ok {
+		seconds, err := strconv.Atoi(v)
+		if err != nil || seconds < 0 {
+			return 0, true
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	if v := h.Get("Expires"); v != "" {
+		expires, err := http.ParseTime(v)
+		if err != nil {
+			return 0, true
+		}
+
+		date := time.Now()
+		if d, err := http.ParseTime(h.Get("Date")); err == nil {
+			date = d
+		}
+
+		if lifetime := expires.Sub(date); lifetime > 0 {
+			return lifetime, true
+		}
+		return 0, true
+	}
+
+	return 0, false
+}
+
+// parseCacheControl parses the Cache-Control header into lower-cased directives
+func parseCacheControl(h http.Header) map[string]string {
+	directives := make(map[string]string)
+	for _, line := range h.Values("Cache-Control") {
+		for _, part := range strings.Split(line, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+			name, value, _ := strings.Cut(part, "=")
+			directives[strings.ToLower(strings.TrimSpace(name))] = strings.Trim(strings.TrimSpace(value), `"`)
+		}
+	}
+	return directives
+}
+
+// hasCacheDirective reports whether the Cache-Control header contains directive
+func hasCacheDirective(h http.Header, directive string) bool {
+	_, ok := parseCacheControl(h)[directive]
+	return ok
+}
+
+func isCacheableMethod(method string) bool {
+	return method == http.MethodGet || method == http.MethodHead
+}
@@ -0,0 +1,419 @@
+package rq
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCacheMaxAge(t *testing.T) {
+	var hits int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Write([]byte("cached body"))
+	}))
+	defer srv.Close()
+
+	store := NewMemoryCache()
+
+	for i := 0; i < 3; i++ {
+		resp := Get(srv.URL).Cache(store).Do()
+		if resp.Error() != nil {
+			t.Fatal(resp.Error())
+		}
+
+		body, _ := resp.String()
+		if body != "cached body" {
+			t.Errorf("want body %q, got %q", "cached body", body)
+		}
+	}
+
+	if got := atomic.LoadInt32(&hits); got != 1 {
+		t.Errorf("want 1 network call, got %d", got)
+	}
+
+	// A different method is a different cache key
+	Head(srv.URL).Cache(store).Do()
+	if got := atomic.LoadInt32(&hits); got != 2 {
+		t.Errorf("want 2 network calls, got %d", got)
+	}
+}
+
+func TestCacheExpires(t *testing.T) {
+	var hits int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		now := time.Now().UTC()
+		w.Header().Set("Date", now.Format(http.TimeFormat))
+		w.Header().Set("Expires", now.Add(time.Hour).Format(http.TimeFormat))
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	store := NewMemoryCache()
+	Get(srv.URL).Cache(store).Do()
+	Get(srv.URL).Cache(store).Do()
+
+	if got := atomic.LoadInt32(&hits); got != 1 {
+		t.Errorf("want 1 network call, got %d", got)
+	}
+}
+
+func TestCacheNoStore(t *testing.T) {
+	tests := map[string]struct {
+		responseCacheControl string
+		requestCacheControl  string
+	}{
+		"response no-store": {
+			responseCacheControl: "no-store, max-age=60",
+		},
+		"request no-store": {
+			responseCacheControl: "max-age=60",
+			requestCacheControl:  "no-store",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var hits int32
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&hits, 1)
+				w.Header().Set("Cache-Control", tt.responseCacheControl)
+				w.Write([]byte("ok"))
+			}))
+			defer srv.Close()
+
+			store := NewMemoryCache()
+			for i := 0; i < 2; i++ {
+				req := Get(srv.URL).Cache(store)
+				if tt.requestCacheControl != "" {
+					req.Header("Cache-Control", tt.requestCacheControl)
+				}
+				req.Do()
+			}
+
+			if got := atomic.LoadInt32(&hits); got != 2 {
+				t.Errorf("want 2 network calls, got %d", got)
+			}
+		})
+	}
+}
+
+func TestCacheETagRevalidation(t *testing.T) {
+	var hits, notModified int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("Cache-Control", "no-cache")
+		w.Header().Set("ETag", `"v1"`)
+
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			atomic.AddInt32(&notModified, 1)
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+
+		w.Write([]byte("original body"))
+	}))
+	defer srv.Close()
+
+	store := NewMemoryCache()
+
+	for i := 0; i < 3; i++ {
+		resp := Get(srv.URL).Cache(store).Do()
+		if resp.Error() != nil {
+			t.Fatal(resp.Error())
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("want status 200, got %d", resp.StatusCode)
+		}
+
+		body, _ := resp.String()
+		if body != "original body" {
+			t.Errorf("want body %q, got %q", "original body", body)
+		}
+	}
+
+	if got := atomic.LoadInt32(&hits); got != 3 {
+		t.Errorf("want 3 network calls, got %d", got)
+	}
+	if got := atomic.LoadInt32(&notModified); got != 2 {
+		t.Errorf("want 2 revalidations, got %d", got)
+	}
+}
+
+func TestCacheValidatorsRunOnCachedResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	store := NewMemoryCache()
+	Get(srv.URL).Cache(store).Do()
+
+	resp := Get(srv.URL).Cache(store).Validate(Validate.BodyContains("missing")).Do()
+	if resp.Error() == nil {
+		t.Error("want validation error on cached response, got nil")
+	}
+
+	resp = Get(srv.URL).Cache(store).Do()
+	if resp.Error() != nil {
+		t.Errorf("want cached entry to be unaffected by earlier validation, got %v", resp.Error())
+	}
+}
+
+func TestMemoryCacheTTL(t *testing.T) {
+	store := NewMemoryCache()
+	store.Set("key", &Response{}, 10*time.Millisecond)
+
+	if _, ok := store.Get("key"); !ok {
+		t.Fatal("want entry before ttl")
+	}
+
+	time.Sleep(20 * time.Millisecond)
+
+	if _, ok := store.Get("key"); ok {
+		t.Error("want entry to expire after ttl")
+	}
+}
+
+func TestResponseAge(t *testing.T) {
+	t.Run("received at", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+		defer srv.Close()
+
+		before := time.Now()
+		resp := Get(srv.URL).Do()
+		if resp.Error() != nil {
+			t.Fatal(resp.Error())
+		}
+
+		if got := resp.ReceivedAt(); got.Before(before) || got.After(time.Now()) {
+			t.Errorf("want ReceivedAt during the request, got %v", got)
+		}
+		if _, err := resp.Age(); err != nil {
+			t.Errorf("want age from the server Date header, got %v", err)
+		}
+	})
+
+	received := time.Now().Add(-2 * time.Second)
+	date := received.Add(-10 * time.Second).UTC().Format(http.TimeFormat)
+
+	tests := map[string]struct {
+		header  http.Header
+		min     time.Duration
+		max     time.Duration
+		wantErr bool
+	}{
+		"date": {
+			header: http.Header{"Date": {date}},
+			min:    11 * time.Second,
+			max:    14 * time.Second,
+		},
+		"age header wins": {
+			header: http.Header{"Date": {date}, "Age": {"100"}},
+			min:    102 * time.Second,
+			max:    103 * time.Second,
+		},
+		"age only": {
+			header: http.Header{"Age": {"5"}},
+			min:    7 * time.Second,
+			max:    8 * time.Second,
+		},
+		"no headers":  {header: http.Header{}, wantErr: true},
+		"invalid age": {header: http.Header{"Age": {"soon"}}, wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := &Response{Response: &http.Response{Header: tt.header}, receivedAt: received}
+
+			age, err := resp.Age()
+			if tt.wantErr {
+				if err == nil {
+					t.Error("want error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if age < tt.min || age > tt.max {
+				t.Errorf("want age between %v and %v, got %v", tt.min, tt.max, age)
+			}
+		})
+	}
+
+	t.Run("nil response", func(t *testing.T) {
+		if _, err := (&Response{}).Age(); err == nil {
+			t.Error("want error, got nil")
+		}
+	})
+}
+
+func TestConditionalRequest(t *testing.T) {
+	modified := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		w.Header().Set("Last-Modified", modified.Format(http.TimeFormat))
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		if since, err := http.ParseTime(r.Header.Get("If-Modified-Since")); err == nil && !modified.After(since) {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Write([]byte(`{"version":1}`))
+	}))
+	defer srv.Close()
+
+	tests := map[string]struct {
+		req             *Request
+		wantNotModified bool
+	}{
+		"unconditional":       {req: Get(srv.URL)},
+		"matching etag":       {req: Get(srv.URL).IfNoneMatch(`"v1"`), wantNotModified: true},
+		"unquoted etag":       {req: IfNoneMatch("v1").URL(srv.URL), wantNotModified: true},
+		"changed etag":        {req: Get(srv.URL).IfNoneMatch("v0")},
+		"not modified since":  {req: Get(srv.URL).IfModifiedSince(modified.Add(time.Hour).In(time.FixedZone("X", 3600))), wantNotModified: true},
+		"modified since then": {req: IfModifiedSince(modified.Add(-time.Hour)).URL(srv.URL)},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := tt.req.Do()
+			if resp.Error() != nil {
+				t.Fatal(resp.Error())
+			}
+
+			if resp.NotModified() != tt.wantNotModified {
+				t.Errorf("want NotModified %v, got %v (status %d)", tt.wantNotModified, resp.NotModified(), resp.StatusCode)
+			}
+
+			v := map[string]int{"version": 0}
+			if err := resp.JSON(&v); err != nil {
+				t.Errorf("want JSON to succeed, got %v", err)
+			}
+			if tt.wantNotModified && v["version"] != 0 {
+				t.Errorf("want value untouched on 304, got version %d", v["version"])
+			}
+			if !tt.wantNotModified && v["version"] != 1 {
+				t.Errorf("want version 1, got %d", v["version"])
+			}
+		})
+	}
+}
+
+func TestCacheVary(t *testing.T) {
+	var hits int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Header().Set("Vary", "Accept-Language")
+		w.Write([]byte(r.Header.Get("Accept-Language")))
+	}))
+	defer srv.Close()
+
+	store := NewMemoryCache()
+
+	for _, lang := range []string{"en", "de", "de"} {
+		resp := Get(srv.URL).Cache(store).Header("Accept-Language", lang).Do()
+		if body, _ := resp.String(); body != lang {
+			t.Errorf("want body %q, got %q", lang, body)
+		}
+	}
+
+	if got := atomic.LoadInt32(&hits); got != 2 {
+		t.Errorf("want 2 network calls, got %d", got)
+	}
+}
+
+func TestCacheAuthorization(t *testing.T) {
+	var hits int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	defer srv.Close()
+
+	store := NewMemoryCache()
+	Get(srv.URL).Cache(store).BearerToken("alice").Do()
+
+	resp := Get(srv.URL).Cache(store).BearerToken("bob").Do()
+	if body, _ := resp.String(); body != "Bearer bob" {
+		t.Errorf("want response for bob, got %q", body)
+	}
+
+	Get(srv.URL).Cache(store).Do()
+	if got := atomic.LoadInt32(&hits); got != 3 {
+		t.Errorf("want 3 network calls, got %d", got)
+	}
+}
+
+func TestCacheCopiesAreIndependent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Header().Set("X-Version", "1")
+		w.Write([]byte("body"))
+	}))
+	defer srv.Close()
+
+	store := NewMemoryCache()
+	Get(srv.URL).Cache(store).Do()
+
+	hit := Get(srv.URL).Cache(store).Do()
+	hit.Header.Set("X-Version", "2")
+	hit.body[0] = 'X'
+
+	resp := Get(srv.URL).Cache(store).Do()
+	if got := resp.Header.Get("X-Version"); got != "1" {
+		t.Errorf("want cached header 1, got %q", got)
+	}
+	if body, _ := resp.String(); body != "body" {
+		t.Errorf("want cached body %q, got %q", "body", body)
+	}
+}
+
+func TestCacheETagEntryLifetime(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	store := NewMemoryCache()
+	Get(srv.URL).Cache(store).Do()
+
+	for key, entry := range store.entries {
+		if entry.expires.IsZero() {
+			t.Errorf("want bounded lifetime for %s, got none", key)
+		}
+	}
+}
+
+func TestMemoryCacheSweep(t *testing.T) {
+	store := NewMemoryCache()
+	for i := range memoryCacheSweepMin {
+		store.Set(strconv.Itoa(i), &Response{}, time.Millisecond)
+	}
+
+	time.Sleep(5 * time.Millisecond)
+	store.Set("fresh", &Response{}, time.Minute)
+
+	if got := len(store.entries); got != 1 {
+		t.Errorf("want expired entries dropped, got %d entries", got)
+	}
+}
@@ -0,0 +1,191 @@
+package rq
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned when a request is rejected by an open circuit breaker
+var ErrCircuitOpen = errors.New("circuit breaker is open")
+
+type circuitState int
+
+const (
+	circuitClosed circuitState = iota
+	circuitOpen
+	circuitHalfOpen
+)
+
+// circuit tracks the breaker state of a single host
+type circuit struct {
+	state        circuitState
+	failures     int
+	firstFailure time.Time
+	openedAt     time.Time
+	probing      bool
+
+	// probe is the request holding the half-open slot
+	probe *http.Request
+}
+
+// CircuitBreaker stops sending requests to a host after Threshold consecutive
+// failures within Window. While open, requests fail fast with ErrCircuitOpen.
+// After Cooldown a single probe request is let through: success closes the
+// circuit, failure opens it again. State is kept per host and is safe for
+// concurrent use.
+type CircuitBreaker struct {
+	Threshold int
+	Window    time.Duration
+	Cooldown  time.Duration
+
+	// IsFailure decides whether a response counts as a failure.
+	// Defaults to network errors and 5xx responses.
+	IsFailure func(*Response) bool
+
+	mu    sync.Mutex
+	hosts map[string]*circuit
+}
+
+// NewCircuitBreaker creates a CircuitBreaker with the default failure predicate
+func NewCircuitBreaker(threshold int, window, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{
+		Threshold: threshold,
+		Window:    window,
+		Cooldown:  cooldown,
+		IsFailure: defaultIsFailure,
+	}
+}
+
+// defaultIsFailure treats network errors and 5xx responses as failures
+func defaultIsFailure(resp *Response) bool {
+	if resp.err != nil || resp.Response == nil {
+		return true
+	}
+	return resp.StatusCode >= 500
+}
+
+// CircuitBreakerMiddleware guards requests with the given CircuitBreaker
+func CircuitBreakerMiddleware(cb *CircuitBreaker) Middleware {
+	return func(r *Request) *Request {
+		if r.err != nil {
+			return r
+		}
+
+		r.beforeSend = append(r.beforeSend, func(req *http.Request) error {
+			return cb.allow(req.URL.Host, req)
+		})
+		r.afterResponse = append(r.afterResponse, func(req *http.Request, resp *Response) {
+			if resp.unsent {
+				cb.release(req.URL.Host, req)
+				return
+			}
+			cb.record(req.URL.Host, resp)
+		})
+
+		return r
+	}
+}
+
+// allow reports whether req may be sent to host
+func (cb *CircuitBreaker) allow(host string, req *http.Request) error {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	c := cb.circuit(host)
+
+	switch c.state {
+	case circuitOpen:
+		if time.Since(c.openedAt) < cb.Cooldown {
+			return fmt.Errorf("%w: %s", ErrCircuitOpen, host)
+		}
+		c.state = circuitHalfOpen
+		c.probing = true
+		c.probe = req
+		return nil
+	case circuitHalfOpen:
+		if c.probing {
+			return fmt.Errorf("%w: %s", ErrCircuitOpen, host)
+		}
+		c.probing = true
+		c.probe = req
+		return nil
+	default:
+		return nil
+	}
+}
+
+// record updates the state of host with the outcome of a request
+func (cb *CircuitBreaker) record(host string, resp *Response) {
+	isFailure := cb.IsFailure
+	if isFailure == nil {
+		isFailure = defaultIsFailure
+	}
+	failed := isFailure(resp)
+
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	c := cb.circuit(host)
+	now := time.Now()
+
+	if c.state == circuitHalfOpen {
+		c.probing = false
+		c.probe = nil
+		if failed {
+			c.state = circuitOpen
+			c.openedAt = now
+		} else {
+			c.state = circuitClosed
+			c.failures = 0
+		}
+		return
+	}
+
+	if !failed {
+		c.failures = 0
+		return
+	}
+
+	if c.failures == 0 || (cb.Window > 0 && now.Sub(c.firstFailure) > cb.Window) {
+		c.failures = 0
+		c.firstFailure = now
+	}
+	c.failures++
+
+	if c.failures >= cb.Threshold {
+		c.state = circuitOpen
+		c.openedAt = now
+		c.failures = 0
+	}
+}
+
+// release frees the half-open slot held by req when it was never sent,
+// so a later request can probe the host instead
+func (cb *CircuitBreaker) release(host string, req *http.Request) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	c := cb.circuit(host)
+	if c.state == circuitHalfOpen && c.probe == req {
+		c.probing = false
+		c.probe = nil
+	}
+}
+
+// circuit returns the state for host, creating it if needed. cb.mu must be held.
+func (cb *CircuitBreaker) circuit(host string) *circuit {
+	if cb.hosts == nil {
+		cb.hosts = make(map[string]*circuit)
+	}
+
+	c, ok := cb.hosts[host]
+	if !ok {
+		c = &circuit{}
+		cb.hosts[host] = c
+	}
+
+	return c
+}
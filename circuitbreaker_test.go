@@ -0,0 +1,194 @@
+package rq
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerTrips(t *testing.T) {
+	var hits int32
+	var healthy atomic.Bool
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		if healthy.Load() {
+			w.WriteHeader(http.StatusOK)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	cb := NewCircuitBreaker(3, time.Minute, 50*time.Millisecond)
+	middleware := CircuitBreakerMiddleware(cb)
+
+	for i := 0; i < 3; i++ {
+		resp := Get(srv.URL).Use(middleware).Do()
+		if resp.Error() != nil {
+			t.Fatalf("want request %d to reach the server, got %v", i+1, resp.Error())
+		}
+	}
+
+	resp := Get(srv.URL).Use(middleware).Do()
+	if !errors.Is(resp.Error(), ErrCircuitOpen) {
+		t.Fatalf("want ErrCircuitOpen, got %v", resp.Error())
+	}
+	if got := atomic.LoadInt32(&hits); got != 3 {
+		t.Errorf("want 3 requests to reach the server, got %d", got)
+	}
+
+	t.Run("half-open probe failure reopens", func(t *testing.T) {
+		time.Sleep(60 * time.Millisecond)
+
+		resp := Get(srv.URL).Use(middleware).Do()
+		if resp.Error() != nil {
+			t.Fatalf("want probe to be sent, got %v", resp.Error())
+		}
+
+		resp = Get(srv.URL).Use(middleware).Do()
+		if !errors.Is(resp.Error(), ErrCircuitOpen) {
+			t.Errorf("want ErrCircuitOpen after failed probe, got %v", resp.Error())
+		}
+	})
+
+	t.Run("half-open probe success closes", func(t *testing.T) {
+		healthy.Store(true)
+		time.Sleep(60 * time.Millisecond)
+
+		for i := 0; i < 3; i++ {
+			resp := Get(srv.URL).Use(middleware).Do()
+			if resp.Error() != nil {
+				t.Fatalf("want circuit to be closed, got %v", resp.Error())
+			}
+		}
+	})
+}
+
+func TestCircuitBreakerConsecutiveFailures(t *testing.T) {
+	cb := NewCircuitBreaker(2, time.Minute, time.Minute)
+	fail := &Response{err: errors.New("network error")}
+	ok := &Response{Response: &http.Response{StatusCode: http.StatusOK}}
+
+	cb.record("host", fail)
+	cb.record("host", ok)
+	cb.record("host", fail)
+
+	if err := cb.allow("host", nil); err != nil {
+		t.Errorf("want success to reset the failure count, got %v", err)
+	}
+
+	cb.record("host", fail)
+	if err := cb.allow("host", nil); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("want ErrCircuitOpen, got %v", err)
+	}
+}
+
+func TestCircuitBreakerWindow(t *testing.T) {
+	cb := NewCircuitBreaker(2, 20*time.Millisecond, time.Minute)
+	fail := &Response{err: errors.New("network error")}
+
+	cb.record("host", fail)
+	time.Sleep(30 * time.Millisecond)
+	cb.record("host", fail)
+
+	if err := cb.allow("host", nil); err != nil {
+		t.Errorf("want failures outside the window not to trip, got %v", err)
+	}
+}
+
+func TestCircuitBreakerPerHost(t *testing.T) {
+	cb := NewCircuitBreaker(1, time.Minute, time.Minute)
+	cb.record("a.example.com", &Response{err: errors.New("network error")})
+
+	if err := cb.allow("a.example.com", nil); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("want ErrCircuitOpen for tripped host, got %v", err)
+	}
+	if err := cb.allow("b.example.com", nil); err != nil {
+		t.Errorf("want other hosts unaffected, got %v", err)
+	}
+}
+
+func TestCircuitBreakerProbeNotSent(t *testing.T) {
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+	}))
+	defer srv.Close()
+
+	cb := NewCircuitBreaker(1, time.Minute, 10*time.Millisecond)
+	cb.record(srv.Listener.Addr().String(), &Response{err: errors.New("network error")})
+	time.Sleep(20 * time.Millisecond)
+
+	errSign := errors.New("sign failed")
+	resp := Get(srv.URL).
+		Use(CircuitBreakerMiddleware(cb)).
+		Use(func(r *Request) *Request {
+			r.beforeSend = append(r.beforeSend, func(*http.Request) error { return errSign })
+			return r
+		}).
+		Do()
+	if !errors.Is(resp.Error(), errSign) {
+		t.Fatalf("want hook error, got %v", resp.Error())
+	}
+
+	resp = Get(srv.URL).Use(CircuitBreakerMiddleware(cb)).Do()
+	if resp.Error() != nil {
+		t.Fatalf("want next request to probe the host, got %v", resp.Error())
+	}
+	if got := atomic.LoadInt32(&hits); got != 1 {
+		t.Errorf("want 1 request to reach the server, got %d", got)
+	}
+
+	resp = Get(srv.URL).Use(CircuitBreakerMiddleware(cb)).Do()
+	if resp.Error() != nil {
+		t.Errorf("want successful probe to close the circuit, got %v", resp.Error())
+	}
+}
+
+func TestCircuitBreakerCustomPredicate(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	cb := NewCircuitBreaker(1, time.Minute, time.Minute)
+	cb.IsFailure = func(resp *Response) bool {
+		return resp.StatusCode == http.StatusTooManyRequests
+	}
+
+	Get(srv.URL).Use(CircuitBreakerMiddleware(cb)).Do()
+
+	resp := Get(srv.URL).Use(CircuitBreakerMiddleware(cb)).Do()
+	if !errors.Is(resp.Error(), ErrCircuitOpen) {
+		t.Errorf("want ErrCircuitOpen, got %v", resp.Error())
+	}
+}
+
+func TestCircuitBreakerConcurrent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer srv.Close()
+
+	cb := NewCircuitBreaker(5, time.Minute, time.Minute)
+
+	var wg sync.WaitGroup
+	for range 20 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			Get(srv.URL).Use(CircuitBreakerMiddleware(cb)).Do()
+		}()
+	}
+	wg.Wait()
+
+	resp := Get(srv.URL).Use(CircuitBreakerMiddleware(cb)).Do()
+	if !errors.Is(resp.Error(), ErrCircuitOpen) {
+		t.Errorf("want ErrCircuitOpen, got %v", resp.Error())
+	}
+}
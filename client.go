@@ -2,6 +2,7 @@ package rq
 
 import (
 	"net/http"
+	"sync/atomic"
 	"time"
 )
 
@@ -11,3 +12,248 @@ var defaultClient = &http.Client{
 
 // ClientOption defines a function type for configuring HTTP clients
 type ClientOption func(*http.Client)
+
+// requestDefaults holds the settings New applies to every request
+type requestDefaults struct {
+	client *http.Client
+	header http.Header
+}
+
+var defaults atomic.Pointer[requestDefaults]
+
+// builtinDefaults are used until SetDefaults is called
+var builtinDefaults = &requestDefaults{client: defaultClient}
+
+// SetDefaults replaces the defaults applied by New, and so by Get, Post and
+// the other constructors, with defaultClient configured by opts. The
+// resulting client is shared by all new requests so connections are pooled.
+// Headers set with WithHeader or WithUserAgent become default request
+// headers that individual requests can replace.
+// Requests created before the call are not affected.
+func SetDefaults(opts ...ClientOption) {
+	client := &http.Client{
+		Transport:     defaultClient.Transport,
+		CheckRedirect: defaultClient.CheckRedirect,
+		Jar:           defaultClient.Jar,
+		Timeout:       defaultClient.Timeout,
+	}
+	for _, opt := range opts {
+		opt(client)
+	}
+
+	header := splitHeaders(client)
+	defaults.Store(&requestDefaults{client: client, header: header})
+}
+
+// ResetDefaults restores the built-in defaults
+func ResetDefaults() {
+	defaults.Store(nil)
+}
+
+// SetDefaultUserAgent sets the User-Agent sent by requests created with New
+// from now on. Requests can still replace it with UserAgent. An empty ua
+// removes the default, leaving Go's own User-Agent. SetDefaults replaces
+// the default, so call SetDefaultUserAgent after it.
+func SetDefaultUserAgent(ua string) {
+	for {
+		old := defaults.Load()
+
+		d := *defaultsOrBuiltin(old)
+		d.header = d.header.Clone()
+		if d.header == nil {
+			d.header = make(http.Header)
+		}
+		if ua == "" {
+			d.header.Del("User-Agent")
+		} else {
+			d.header.Set("User-Agent", ua)
+		}
+
+		if defaults.CompareAndSwap(old, &d) {
+			return
+		}
+	}
+}
+
+// currentDefaults returns the defaults set with SetDefaults, if any
+func currentDefaults() *requestDefaults {
+	return defaultsOrBuiltin(defaults.Load())
+}
+
+// defaultsOrBuiltin returns d, or the built-in defaults if d is nil
+func defaultsOrBuiltin(d *requestDefaults) *requestDefaults {
+	if d != nil {
+		return d
+	}
+	return builtinDefaults
+}
+
+// WithTimeout sets the client timeout
+func WithTimeout(d time.Duration) ClientOption {
+	return func(c *http.Client) {
+		c.Timeout = d
+	}
+}
+
+// WithClient copies the settings of client
+func WithClient(client *http.Client) ClientOption {
+	return func(c *http.Client) {
+		c.Transport = client.Transport
+		c.CheckRedirect = client.CheckRedirect
+		c.Jar = client.Jar
+		c.Timeout = client.Timeout
+	}
+}
+
+// WithTransport sets the client transport
+func WithTransport(transport http.RoundTripper) ClientOption {
+	return func(c *http.Client) {
+		c.Transport = transport
+	}
+}
+
+// WithCookieJar sets the client cookie jar
+func WithCookieJar(jar http.CookieJar) ClientOption {
+	return func(c *http.Client) {
+		c.Jar = jar
+	}
+}
+
+// WithCheckRedirect sets the client redirect policy
+func WithCheckRedirect(fn func(req *http.Request, via []*http.Request) error) ClientOption {
+	return func(c *http.Client) {
+		c.CheckRedirect = fn
+	}
+}
+
+// WithHeader sets a header on every request sent by the client, unless the
+// request sets it itself
+func WithHeader(key, value string) ClientOption {
+	return func(c *http.Client) {
+		c.Transport = &headerTransport{
+			base:   c.Transport,
+			header: http.Header{http.CanonicalHeaderKey(key): {value}},
+		}
+	}
+}
+
+// WithUserAgent sets the User-Agent of every request sent by the client,
+// unless the request sets it itself
+func WithUserAgent(userAgent string) ClientOption {
+	return WithHeader("User-Agent", userAgent)
+}
+
+// NewHTTPClient creates an *http.Client configured by opts
+func NewHTTPClient(opts ...ClientOption) *http.Client {
+	client := &http.Client{}
+	for _, opt := range opts {
+		opt(client)
+	}
+	return client
+}
+
+// ClientOptions creates a new request with a client configured by opts
+func ClientOptions(opts ...ClientOption) *Request {
+	return New().ClientOptions(opts...)
+}
+
+// ClientOptions applies opts to a copy of the request client. Headers set
+// with WithHeader or WithUserAgent are sent unless the request sets them.
+func (r *Request) ClientOptions(opts ...ClientOption) *Request {
+	if r.err != nil {
+		return r
+	}
+
+	client := r.cloneClient()
+	for _, opt := range opts {
+		opt(client)
+	}
+
+	if header := splitHeaders(client); len(header) > 0 {
+		defaults := r.defaults.Clone()
+		if defaults == nil {
+			defaults = make(http.Header)
+		}
+		for key, values := range header {
+			defaults[key] = values
+		}
+		r.defaults = defaults
+	}
+
+	r.client = client
+	return r
+}
+
+// headerTransport adds default headers to outgoing requests
+type headerTransport struct {
+	base   http.RoundTripper
+	header http.Header
+}
+
+// RoundTrip implements the RoundTripper interface
+func (t *headerTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	req = req.Clone(req.Context())
+	for key, values := range t.header {
+		if _, ok := req.Header[key]; !ok {
+			req.Header[key] = values
+		}
+	}
+
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	return base.RoundTrip(req)
+}
+
+// splitHeaders unwraps the header transports of client and returns their
+// headers, so they can be set on requests instead and the client keeps a
+// transport that other builders can configure
+func splitHeaders(client *http.Client) http.Header {
+	var layers []http.Header
+	for {
+		t, ok := client.Transport.(*headerTransport)
+		if !ok {
+			break
+		}
+		layers = append(layers, t.header)
+		client.Transport = t.base
+	}
+
+	// Inner layers were applied first, so outer ones take precedence
+	header := make(http.Header)
+	for i := len(layers) - 1; i >= 0; i-- {
+		for key, values := range layers[i] {
+			header[key] = values
+		}
+	}
+	return header
+}
+
+// cloneClient returns a copy of the request client that is safe to modify
+func (r *Request) cloneClient() *http.Client {
+	if r.client == nil {
+		return &http.Client{}
+	}
+
+	return &http.Client{
+		Transport:     r.client.Transport,
+		CheckRedirect: r.client.CheckRedirect,
+		Jar:           r.client.Jar,
+		Timeout:       r.client.Timeout,
+	}
+}
+
+// withoutClientTimeout returns a copy of r whose client has no timeout.
+// http.Client.Timeout includes reading the body and would cut off
+// long-lived streams, which are bounded by Timeout and the context instead.
+func (r *Request) withoutClientTimeout() *Request {
+	if r.client == nil || r.client.Timeout == 0 {
+		return r
+	}
+
+	send := *r
+	send.client = r.cloneClient()
+	send.client.Timeout = 0
+	return &send
+}
@@ -0,0 +1,234 @@
+package rq
+
+import (
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSetDefaults(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Header.Get("User-Agent") + "|" + r.Header.Get("X-Team")))
+	}))
+	defer srv.Close()
+
+	SetDefaults(
+		WithTimeout(5*time.Second),
+		WithUserAgent("app/1.0"),
+		WithHeader("X-Team", "core"),
+	)
+	defer ResetDefaults()
+
+	t.Run("applied to new requests", func(t *testing.T) {
+		r := Get(srv.URL)
+		if r.client.Timeout != 5*time.Second {
+			t.Errorf("want timeout 5s, got %v", r.client.Timeout)
+		}
+		if _, ok := r.client.Transport.(*headerTransport); ok {
+			t.Error("want header options to be moved to request headers")
+		}
+
+		got, err := r.Do().String()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != "app/1.0|core" {
+			t.Errorf("want app/1.0|core, got %q", got)
+		}
+	})
+
+	t.Run("request headers override defaults", func(t *testing.T) {
+		got, err := Get(srv.URL).Header("User-Agent", "custom").Do().String()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != "custom|core" {
+			t.Errorf("want custom|core, got %q", got)
+		}
+	})
+
+	t.Run("requests do not share headers", func(t *testing.T) {
+		Get(srv.URL).Header("X-Team", "other")
+		if got := New().requestHeader().Get("X-Team"); got != "core" {
+			t.Errorf("want default X-Team core, got %q", got)
+		}
+	})
+
+	t.Run("concurrent New", func(t *testing.T) {
+		var wg sync.WaitGroup
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				New().Header("X-Team", "mine")
+			}()
+		}
+		wg.Wait()
+	})
+
+	t.Run("WithClient", func(t *testing.T) {
+		shared := &http.Client{Timeout: time.Second}
+		SetDefaults(WithClient(shared))
+		if got := New().client.Timeout; got != time.Second {
+			t.Errorf("want timeout 1s, got %v", got)
+		}
+	})
+
+	ResetDefaults()
+	if r := New(); r.client != defaultClient || len(r.defaults) != 0 {
+		t.Error("want built-in defaults after ResetDefaults")
+	}
+}
+
+func TestWithHeaderTransport(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Header.Get("User-Agent")))
+	}))
+	defer srv.Close()
+
+	client := &http.Client{}
+	WithUserAgent("app/1.0")(client)
+
+	resp, err := client.Get(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	got, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != "app/1.0" {
+		t.Errorf("want User-Agent app/1.0, got %q", got)
+	}
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	jar, _ := cookiejar.New(nil)
+	transport := &http.Transport{}
+	checkRedirect := func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }
+
+	client := NewHTTPClient(
+		WithTimeout(3*time.Second),
+		WithTransport(transport),
+		WithCookieJar(jar),
+		WithCheckRedirect(checkRedirect),
+	)
+
+	if client.Timeout != 3*time.Second {
+		t.Errorf("want timeout 3s, got %v", client.Timeout)
+	}
+	if client.Transport != transport {
+		t.Error("want transport to be set")
+	}
+	if client.Jar != jar {
+		t.Error("want cookie jar to be set")
+	}
+	if client.CheckRedirect == nil {
+		t.Error("want CheckRedirect to be set")
+	}
+}
+
+func TestClientOptions(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/redirect" {
+			http.Redirect(w, r, "/", http.StatusFound)
+			return
+		}
+		w.Write([]byte(r.Header.Get("User-Agent")))
+	}))
+	defer srv.Close()
+
+	t.Run("CheckRedirect", func(t *testing.T) {
+		resp := Get(srv.URL + "/redirect").
+			ClientOptions(WithCheckRedirect(func(*http.Request, []*http.Request) error {
+				return http.ErrUseLastResponse
+			})).
+			Do()
+		if resp.StatusCode != http.StatusFound {
+			t.Errorf("want status 302, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("UserAgent", func(t *testing.T) {
+		got, err := Get(srv.URL).ClientOptions(WithUserAgent("app/2.0")).InsecureSkipVerify(true).Do().String()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != "app/2.0" {
+			t.Errorf("want User-Agent app/2.0, got %q", got)
+		}
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		r := Get(srv.URL).ClientOptions(WithTimeout(time.Second))
+		if r.client.Timeout != time.Second {
+			t.Errorf("want timeout 1s, got %v", r.client.Timeout)
+		}
+		if defaultClient.Timeout != 30*time.Second {
+			t.Error("want default client to be left unchanged")
+		}
+	})
+}
+
+func TestUserAgent(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ua, ok := r.Header["User-Agent"]
+		if !ok {
+			w.Write([]byte("<none>"))
+			return
+		}
+		w.Write([]byte(ua[0]))
+	}))
+	defer srv.Close()
+
+	SetDefaultUserAgent("app/1.0")
+	defer ResetDefaults()
+
+	tests := map[string]struct {
+		req  func() *Request
+		want string
+	}{
+		"default": {
+			req:  func() *Request { return Get(srv.URL) },
+			want: "app/1.0",
+		},
+		"per request": {
+			req:  func() *Request { return Get(srv.URL).UserAgent("custom/2.0") },
+			want: "custom/2.0",
+		},
+		"empty removes header": {
+			req:  func() *Request { return Get(srv.URL).UserAgent("") },
+			want: "<none>",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := tt.req().Do().String()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("want User-Agent %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("clearing the default", func(t *testing.T) {
+		SetDefaultUserAgent("")
+		got, err := Get(srv.URL).Do().String()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.HasPrefix(got, "Go-http-client") {
+			t.Errorf("want Go's User-Agent, got %q", got)
+		}
+	})
+}
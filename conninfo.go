@@ -0,0 +1,49 @@
+package rq
+
+import (
+	"net/http/httptrace"
+	"sync"
+)
+
+// CaptureConnInfo creates a new request that records connection details
+func CaptureConnInfo() *Request {
+	return New().CaptureConnInfo()
+}
+
+// CaptureConnInfo records the remote address the request connected to and
+// whether the connection was reused in Response.RemoteAddr and
+// Response.ConnReused.
+func (r *Request) CaptureConnInfo() *Request {
+	if r.err != nil {
+		return r
+	}
+	r.captureConnInfo = true
+	return r
+}
+
+// connInfoTrace records the connection obtained for a request
+type connInfoTrace struct {
+	mu         sync.Mutex
+	remoteAddr string
+	reused     bool
+}
+
+// gotConn is the httptrace GotConn hook recording into t
+func (t *connInfoTrace) gotConn(info httptrace.GotConnInfo) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if info.Conn != nil {
+		t.remoteAddr = info.Conn.RemoteAddr().String()
+	}
+	t.reused = info.Reused
+}
+
+// apply copies the recorded connection details to response
+func (t *connInfoTrace) apply(response *Response) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	response.RemoteAddr = t.remoteAddr
+	response.ConnReused = t.reused
+}
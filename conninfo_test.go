@@ -0,0 +1,42 @@
+package rq
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestCaptureConnInfo(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	client := &http.Client{Transport: &http.Transport{}}
+	wantAddr := strings.TrimPrefix(srv.URL, "http://")
+
+	resp := Get(srv.URL).Client(client).CaptureConnInfo().Do()
+	if resp.Error() != nil {
+		t.Fatal(resp.Error())
+	}
+	if resp.RemoteAddr != wantAddr {
+		t.Errorf("want remote addr %s, got %s", wantAddr, resp.RemoteAddr)
+	}
+	if resp.ConnReused {
+		t.Error("want first connection not to be reused")
+	}
+
+	resp = Get(srv.URL).Client(client).CaptureConnInfo().Do()
+	if resp.Error() != nil {
+		t.Fatal(resp.Error())
+	}
+	if !resp.ConnReused {
+		t.Error("want second connection to be reused")
+	}
+
+	resp = Get(srv.URL).Client(client).Do()
+	if resp.RemoteAddr != "" {
+		t.Errorf("want no remote addr without CaptureConnInfo, got %s", resp.RemoteAddr)
+	}
+}
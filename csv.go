@@ -0,0 +1,94 @@
+package rq
+
+import (
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+)
+
+type csvConfig struct {
+	comma  rune
+	header bool
+}
+
+// CSVOption configures CSV decoding
+type CSVOption func(*csvConfig)
+
+// CSVDelimiter sets the field delimiter (default ',')
+func CSVDelimiter(delimiter rune) CSVOption {
+	return func(c *csvConfig) {
+		c.comma = delimiter
+	}
+}
+
+// CSVHeader sets whether the first record is a header row.
+// Header rows are skipped by CSV and used as keys by CSVMap.
+func CSVHeader(header bool) CSVOption {
+	return func(c *csvConfig) {
+		c.header = header
+	}
+}
+
+// CSV decodes the response body as CSV, calling fn for each record
+func (r *Response) CSV(fn func(record []string) error, opts ...CSVOption) error {
+	return r.readCSV(opts, func(_, record []string) error {
+		return fn(record)
+	})
+}
+
+// CSVMap decodes a headered CSV body, calling fn with each record keyed by column name
+func (r *Response) CSVMap(fn func(record map[string]string) error, opts ...CSVOption) error {
+	opts = append([]CSVOption{CSVHeader(true)}, opts...)
+
+	return r.readCSV(opts, func(header, record []string) error {
+		m := make(map[string]string, len(header))
+		for i, name := range header {
+			if i < len(record) {
+				m[name] = record[i]
+			}
+		}
+		return fn(m)
+	})
+}
+
+// readCSV reads records from the body, passing the header row alongside each record
+func (r *Response) readCSV(opts []CSVOption, fn func(header, record []string) error) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	config := csvConfig{comma: ','}
+	for _, opt := range opts {
+		opt(&config)
+	}
+
+	reader := csv.NewReader(r.bodyReader())
+	reader.Comma = config.comma
+
+	var header []string
+	if config.header {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("decode CSV header: %w", err)
+		}
+		header = record
+	}
+
+	for {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("decode CSV: %w", err)
+		}
+
+		if err := fn(header, record); err != nil {
+			return err
+		}
+	}
+}
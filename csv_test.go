@@ -0,0 +1,93 @@
+package rq
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestResponseCSV(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/csv")
+		switch r.URL.Path {
+		case "/semicolon":
+			w.Write([]byte("name;age\nalice;30\nbob;25\n"))
+		default:
+			w.Write([]byte("name,age\nalice,30\nbob,25\n"))
+		}
+	}))
+	defer srv.Close()
+
+	t.Run("all records", func(t *testing.T) {
+		var rows []string
+		err := Get(srv.URL).Do().CSV(func(record []string) error {
+			rows = append(rows, strings.Join(record, "|"))
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := "name|age,alice|30,bob|25"
+		if got := strings.Join(rows, ","); got != want {
+			t.Errorf("want %q, got %q", want, got)
+		}
+	})
+
+	t.Run("skips header and custom delimiter", func(t *testing.T) {
+		var rows []string
+		err := Get(srv.URL+"/semicolon").Do().CSV(func(record []string) error {
+			rows = append(rows, strings.Join(record, "|"))
+			return nil
+		}, CSVDelimiter(';'), CSVHeader(true))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := "alice|30,bob|25"
+		if got := strings.Join(rows, ","); got != want {
+			t.Errorf("want %q, got %q", want, got)
+		}
+	})
+
+	t.Run("map records", func(t *testing.T) {
+		var names []string
+		err := Get(srv.URL).Do().CSVMap(func(record map[string]string) error {
+			names = append(names, record["name"]+"="+record["age"])
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		want := "alice=30,bob=25"
+		if got := strings.Join(names, ","); got != want {
+			t.Errorf("want %q, got %q", want, got)
+		}
+	})
+
+	t.Run("callback error stops decoding", func(t *testing.T) {
+		stop := errors.New("stop")
+		var calls int
+		err := Get(srv.URL).Do().CSV(func(record []string) error {
+			calls++
+			return stop
+		})
+		if !errors.Is(err, stop) {
+			t.Errorf("want %v, got %v", stop, err)
+		}
+		if calls != 1 {
+			t.Errorf("want 1 call, got %d", calls)
+		}
+	})
+
+	t.Run("malformed CSV", func(t *testing.T) {
+		resp := &Response{body: []byte("a,\"b\nc")}
+		err := resp.CSV(func(record []string) error { return nil })
+		if err == nil {
+			t.Error("want decode error, got nil")
+		}
+	})
+}
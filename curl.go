@@ -0,0 +1,80 @@
+package rq
+
+import (
+	"bytes"
+	"net/http"
+	"slices"
+	"strings"
+	"unicode/utf8"
+)
+
+// ToCurl renders the request as a curl command for debugging and bug reports.
+// It includes the method, the URL with query parameters, headers, cookies
+// and the body, all single-quoted for POSIX shells. Headers added by
+// middleware applied with Use are included; those set by hooks right before
+// sending, such as AWSV4 signatures, are not. A binary body is rendered as
+// --data-binary @- and has to be piped into the command.
+func (r *Request) ToCurl() (string, error) {
+	if r.err != nil {
+		return "", r.err
+	}
+
+	u, err := r.requestURL()
+	if err != nil {
+		return "", err
+	}
+
+	body := r.bufferedBody()
+	if r.err != nil {
+		return "", r.err
+	}
+
+	parts := []string{"curl"}
+
+	if r.method != http.MethodGet || body != nil {
+		parts = append(parts, "-X", shellQuote(r.method))
+	}
+
+	header := r.requestHeader()
+	names := make([]string, 0, len(header))
+	for name := range header {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	for _, name := range names {
+		for _, value := range header[name] {
+			parts = append(parts, "-H", shellQuote(name+": "+value))
+		}
+	}
+
+	if len(r.cookies) > 0 {
+		cookies := make([]string, len(r.cookies))
+		for i, c := range r.cookies {
+			cookies[i] = c.Name + "=" + c.Value
+		}
+		parts = append(parts, "-b", shellQuote(strings.Join(cookies, "; ")))
+	}
+
+	if body != nil {
+		if isBinary(body) {
+			parts = append(parts, "--data-binary", "@-")
+		} else {
+			parts = append(parts, "--data-raw", shellQuote(string(body)))
+		}
+	}
+
+	parts = append(parts, shellQuote(u.String()))
+
+	return strings.Join(parts, " "), nil
+}
+
+// shellQuote single-quotes s for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
+
+// isBinary reports whether data cannot be passed as a command-line argument
+func isBinary(data []byte) bool {
+	return !utf8.Valid(data) || bytes.IndexByte(data, 0) >= 0
+}
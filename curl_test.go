@@ -0,0 +1,63 @@
+package rq
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestToCurl(t *testing.T) {
+	tests := map[string]struct {
+		req  *Request
+		want string
+	}{
+		"simple get": {
+			req:  Get("https://api.example.com/users"),
+			want: `curl 'https://api.example.com/users'`,
+		},
+		"query and headers": {
+			req: Get("https://api.example.com/users").
+				QueryParam("q", "john doe").
+				Header("X-B", "2").
+				Header("Accept", "application/json"),
+			want: `curl -H 'Accept: application/json' -H 'X-B: 2' 'https://api.example.com/users?q=john+doe'`,
+		},
+		"json body with quote": {
+			req:  Post("https://api.example.com/users").BodyJSON(map[string]string{"name": "O'Brien"}),
+			want: `curl -X 'POST' -H 'Content-Type: application/json' --data-raw '{"name":"O'\''Brien"}' 'https://api.example.com/users'`,
+		},
+		"basic auth and cookies": {
+			req: Delete("https://api.example.com/users/1").
+				BasicAuth("user", "pass").
+				Cookies(&http.Cookie{Name: "session", Value: "abc"}),
+			want: `curl -X 'DELETE' -H 'Authorization: Basic dXNlcjpwYXNz' -b 'session=abc' 'https://api.example.com/users/1'`,
+		},
+		"binary body": {
+			req:  Put("https://api.example.com/blob").BodyBytes([]byte{0x00, 0xff}),
+			want: `curl -X 'PUT' --data-binary @- 'https://api.example.com/blob'`,
+		},
+		"middleware headers": {
+			req: Get("https://api.example.com").Use(func(r *Request) *Request {
+				return r.Header("X-Middleware", "yes")
+			}),
+			want: `curl -H 'X-Middleware: yes' 'https://api.example.com'`,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := tt.req.ToCurl()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("want\n%s\ngot\n%s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestToCurlError(t *testing.T) {
+	if _, err := New().ToCurl(); err == nil {
+		t.Error("want error for missing URL, got nil")
+	}
+}
@@ -0,0 +1,210 @@
+package rq
+
+import (
+	"compress/gzip"
+	"compress/zlib"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// ErrBodyTooLarge is returned when a response body exceeds the limit set with MaxBodySize
+var ErrBodyTooLarge = errors.New("response body too large")
+
+// MaxBodySize creates a new request with a response body size limit
+func MaxBodySize(n int64) *Request {
+	return New().MaxBodySize(n)
+}
+
+// MaxBodySize limits how many bytes of the response body are read. The limit
+// applies to the decompressed body, so a small compressed payload cannot
+// expand past it. Reading more fails with ErrBodyTooLarge. A limit <= 0
+// means no limit.
+func (r *Request) MaxBodySize(n int64) *Request {
+	if r.err != nil {
+		return r
+	}
+	r.maxBodySize = n
+	return r
+}
+
+// Decompress creates a new request that decodes compressed responses
+func Decompress() *Request {
+	return New().Decompress()
+}
+
+// Decompress decodes response bodies whose Content-Encoding is gzip, deflate
+// or an encoding added with RegisterDecoder, removing the Content-Encoding
+// and Content-Length headers. It is only needed when Accept-Encoding is set
+// explicitly; otherwise net/http negotiates and decodes gzip itself. Other
+// encodings, including zstd, are left as they are. Without Decompress a
+// compressed body is returned as received.
+func (r *Request) Decompress() *Request {
+	if r.err != nil {
+		return r
+	}
+	r.decompress = true
+	return r
+}
+
+// decoders maps a Content-Encoding to the decompressor used for it
+var (
+	decodersMu sync.RWMutex
+	decoders   = map[string]func(io.Reader) (io.ReadCloser, error){
+		"gzip": func(r io.Reader) (io.ReadCloser, error) {
+			return gzip.NewReader(r)
+		},
+		"x-gzip": func(r io.Reader) (io.ReadCloser, error) {
+			return gzip.NewReader(r)
+		},
+		"deflate": zlib.NewReader,
+	}
+)
+
+// RegisterDecoder makes responses with the given Content-Encoding decode
+// through newDecoder. It is how encodings outside the standard library,
+// such as brotli, are supported without adding a dependency to this
+// package, for example:
+//
+//	rq.RegisterDecoder("br", func(r io.Reader) (io.ReadCloser, error) {
+//		return io.NopCloser(brotli.NewReader(r)), nil
+//	})
+func RegisterDecoder(encoding string, newDecoder func(io.Reader) (io.ReadCloser, error)) {
+	decodersMu.Lock()
+	defer decodersMu.Unlock()
+	decoders[strings.ToLower(encoding)] = newDecoder
+}
+
+// decoder returns the decompressor registered for encoding
+func decoder(encoding string) (func(io.Reader) (io.ReadCloser, error), bool) {
+	decodersMu.RLock()
+	defer decodersMu.RUnlock()
+	newDecoder, ok := decoders[encoding]
+	return newDecoder, ok
+}
+
+// AcceptBrotli creates a new request that accepts brotli-encoded responses
+func AcceptBrotli() *Request {
+	return New().AcceptBrotli()
+}
+
+// AcceptBrotli advertises brotli, gzip and deflate in Accept-Encoding and
+// turns on Decompress. Brotli bodies are only decoded once a decoder is
+// registered with RegisterDecoder; otherwise they are left encoded and the
+// response keeps its Content-Encoding: br header so callers can tell.
+func (r *Request) AcceptBrotli() *Request {
+	if r.err != nil {
+		return r
+	}
+	r.header().Set("Accept-Encoding", "br, gzip, deflate")
+	r.decompress = true
+	return r
+}
+
+// decodeBody wraps a compressed response body with a decompressor for Decompress.
+// net/http already decodes gzip when it negotiated the encoding itself;
+// this handles encodings requested explicitly through Accept-Encoding.
+func decodeBody(resp *http.Response) {
+	if resp.Uncompressed || resp.Body == nil || resp.Body == http.NoBody {
+		return
+	}
+	if resp.Request != nil && resp.Request.Method == http.MethodHead {
+		return
+	}
+	if resp.StatusCode == http.StatusNoContent || resp.StatusCode == http.StatusNotModified {
+		return
+	}
+
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	newDecoder, ok := decoder(encoding)
+	if !ok {
+		return
+	}
+
+	resp.Body = &decodingReader{body: resp.Body, newDecoder: newDecoder}
+	resp.Header.Del("Content-Encoding")
+	resp.Header.Del("Content-Length")
+	resp.ContentLength = -1
+	resp.Uncompressed = true
+}
+
+// decodingReader creates its decompressor lazily so an empty body reads as EOF
+type decodingReader struct {
+	body       io.ReadCloser
+	newDecoder func(io.Reader) (io.ReadCloser, error)
+	decoder    io.ReadCloser
+	err        error
+}
+
+func (d *decodingReader) Read(p []byte) (int, error) {
+	if d.err != nil {
+		return 0, d.err
+	}
+	if d.decoder == nil {
+		d.decoder, d.err = d.newDecoder(d.body)
+		if d.err != nil {
+			return 0, d.err
+		}
+	}
+	return d.decoder.Read(p)
+}
+
+func (d *decodingReader) Close() error {
+	if d.decoder != nil {
+		_ = d.decoder.Close()
+	}
+	return d.body.Close()
+}
+
+// readBody reads body up to limit bytes, failing with ErrBodyTooLarge past it
+func readBody(body io.Reader, limit int64) ([]byte, error) {
+	if limit <= 0 {
+		return io.ReadAll(body)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(body, limit+1))
+	if err != nil {
+		return data, err
+	}
+	if int64(len(data)) > limit {
+		return data[:limit], fmt.Errorf("%w: limit is %d bytes", ErrBodyTooLarge, limit)
+	}
+
+	return data, nil
+}
+
+// limitBody wraps a streamed body so reading past limit fails with ErrBodyTooLarge
+func limitBody(body io.ReadCloser, limit int64) io.ReadCloser {
+	if limit <= 0 {
+		return body
+	}
+	return &limitedBody{ReadCloser: body, limit: limit, remaining: limit}
+}
+
+// limitedBody reads at most one byte past the limit to detect an oversized body
+type limitedBody struct {
+	io.ReadCloser
+	limit     int64
+	remaining int64
+}
+
+func (l *limitedBody) Read(p []byte) (int, error) {
+	if l.remaining < 0 {
+		return 0, fmt.Errorf("%w: limit is %d bytes", ErrBodyTooLarge, l.limit)
+	}
+
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+
+	n, err := l.ReadCloser.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n - 1, fmt.Errorf("%w: limit is %d bytes", ErrBodyTooLarge, l.limit)
+	}
+
+	return n, err
+}
@@ -0,0 +1,201 @@
+package rq
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"encoding/base64"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestDecompressBody(t *testing.T) {
+	var gz, zl bytes.Buffer
+	gw := gzip.NewWriter(&gz)
+	gw.Write([]byte("hello gzip"))
+	gw.Close()
+	zw := zlib.NewWriter(&zl)
+	zw.Write([]byte("hello deflate"))
+	zw.Close()
+
+	tests := map[string]struct {
+		encoding string
+		payload  []byte
+		want     string
+	}{
+		"gzip": {
+			encoding: "gzip",
+			payload:  gz.Bytes(),
+			want:     "hello gzip",
+		},
+		"deflate": {
+			encoding: "deflate",
+			payload:  zl.Bytes(),
+			want:     "hello deflate",
+		},
+		"identity": {
+			encoding: "",
+			payload:  []byte("plain"),
+			want:     "plain",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.encoding != "" {
+					w.Header().Set("Content-Encoding", tt.encoding)
+				}
+				w.Write(tt.payload)
+			}))
+			defer srv.Close()
+
+			resp := Get(srv.URL).Header("Accept-Encoding", "gzip, deflate").Decompress().Do()
+			if resp.Error() != nil {
+				t.Fatal(resp.Error())
+			}
+
+			body, _ := resp.String()
+			if body != tt.want {
+				t.Errorf("want body %q, got %q", tt.want, body)
+			}
+			if got := resp.Header.Get("Content-Encoding"); got != "" {
+				t.Errorf("want Content-Encoding to be removed, got %q", got)
+			}
+		})
+	}
+
+	t.Run("not requested", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Encoding", "gzip")
+			w.Write(gz.Bytes())
+		}))
+		defer srv.Close()
+
+		resp := Get(srv.URL).Header("Accept-Encoding", "gzip").Do()
+		if resp.Error() != nil {
+			t.Fatal(resp.Error())
+		}
+
+		if body, _ := resp.Bytes(); !bytes.Equal(body, gz.Bytes()) {
+			t.Error("want body to be returned as received")
+		}
+		if got := resp.Header.Get("Content-Encoding"); got != "gzip" {
+			t.Errorf("want Content-Encoding gzip to be kept, got %q", got)
+		}
+	})
+}
+
+func TestMaxBodySizeDecompressionBomb(t *testing.T) {
+	var bomb bytes.Buffer
+	gw := gzip.NewWriter(&bomb)
+	gw.Write(bytes.Repeat([]byte{0}, 10<<20))
+	gw.Close()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Encoding", "gzip")
+		w.Write(bomb.Bytes())
+	}))
+	defer srv.Close()
+
+	tests := map[string]*Request{
+		"transport decompression":  Get(srv.URL),
+		"explicit Accept-Encoding": Get(srv.URL).Header("Accept-Encoding", "gzip").Decompress(),
+	}
+
+	for name, req := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := req.MaxBodySize(1024).Do()
+			if !errors.Is(resp.Error(), ErrBodyTooLarge) {
+				t.Fatalf("want ErrBodyTooLarge, got %v", resp.Error())
+			}
+		})
+	}
+}
+
+func TestMaxBodySize(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("a", 100)))
+	}))
+	defer srv.Close()
+
+	tests := map[string]struct {
+		limit   int64
+		wantErr bool
+	}{
+		"no limit":     {limit: 0},
+		"exact limit":  {limit: 100},
+		"over limit":   {limit: 99, wantErr: true},
+		"large enough": {limit: 1 << 20},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := Get(srv.URL).MaxBodySize(tt.limit).Do()
+			if tt.wantErr {
+				if !errors.Is(resp.Error(), ErrBodyTooLarge) {
+					t.Errorf("want ErrBodyTooLarge, got %v", resp.Error())
+				}
+				return
+			}
+			if resp.Error() != nil {
+				t.Errorf("want no error, got %v", resp.Error())
+			}
+		})
+	}
+}
+
+func TestAcceptBrotli(t *testing.T) {
+	payload := base64.StdEncoding.EncodeToString([]byte("hello brotli"))
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Accept-Encoding"); got != "br, gzip, deflate" {
+			t.Errorf("want Accept-Encoding %q, got %q", "br, gzip, deflate", got)
+		}
+		w.Header().Set("Content-Encoding", "br")
+		w.Write([]byte(payload))
+	}))
+	defer srv.Close()
+
+	t.Run("no decoder", func(t *testing.T) {
+		resp := AcceptBrotli().URL(srv.URL).Do()
+		if resp.Error() != nil {
+			t.Fatal(resp.Error())
+		}
+
+		if body, _ := resp.String(); body != payload {
+			t.Errorf("want encoded body %q, got %q", payload, body)
+		}
+		if got := resp.Header.Get("Content-Encoding"); got != "br" {
+			t.Errorf("want Content-Encoding br to be kept, got %q", got)
+		}
+	})
+
+	t.Run("registered decoder", func(t *testing.T) {
+		// base64 stands in for brotli, which is not in the standard library
+		RegisterDecoder("BR", func(r io.Reader) (io.ReadCloser, error) {
+			return io.NopCloser(base64.NewDecoder(base64.StdEncoding, r)), nil
+		})
+		t.Cleanup(func() {
+			decodersMu.Lock()
+			delete(decoders, "br")
+			decodersMu.Unlock()
+		})
+
+		resp := Get(srv.URL).AcceptBrotli().Do()
+		if resp.Error() != nil {
+			t.Fatal(resp.Error())
+		}
+
+		if body, _ := resp.String(); body != "hello brotli" {
+			t.Errorf("want decoded body %q, got %q", "hello brotli", body)
+		}
+		if got := resp.Header.Get("Content-Encoding"); got != "" {
+			t.Errorf("want Content-Encoding to be removed, got %q", got)
+		}
+	})
+}
@@ -0,0 +1,69 @@
+package rq
+
+import (
+	"fmt"
+	"net/http"
+)
+
+// HTTPError is returned by AsHTTPError for 4xx and 5xx responses
+type HTTPError struct {
+	StatusCode int
+	Status     string
+	Body       []byte
+	URL        string
+}
+
+// Error implements the error interface
+func (e *HTTPError) Error() string {
+	if e.URL == "" {
+		return fmt.Sprintf("http error: %s", e.Status)
+	}
+	return fmt.Sprintf("http error: %s from %s", e.Status, e.URL)
+}
+
+// MustError is the value MustDo and MustDoContext panic with, so a recover
+// site can tell which request failed. It implements error and unwraps to
+// the error the request failed with.
+type MustError struct {
+	Method string
+	URL    string
+	Err    error
+}
+
+// Error implements the error interface
+func (e *MustError) Error() string {
+	return fmt.Sprintf("%s %s: %v", e.Method, e.URL, e.Err)
+}
+
+// Unwrap returns the error the request failed with
+func (e *MustError) Unwrap() error {
+	return e.Err
+}
+
+// AsHTTPError returns an *HTTPError for 4xx and 5xx responses and nil otherwise.
+// If the request itself failed, the underlying error is returned instead.
+func (r *Response) AsHTTPError() error {
+	if r.err != nil {
+		return r.err
+	}
+	if r.Response == nil || r.StatusCode < 400 {
+		return nil
+	}
+
+	status := r.Status
+	if status == "" {
+		status = fmt.Sprintf("%d %s", r.StatusCode, http.StatusText(r.StatusCode))
+	}
+
+	var u string
+	if r.Request != nil && r.Request.URL != nil {
+		u = r.Request.URL.String()
+	}
+
+	return &HTTPError{
+		StatusCode: r.StatusCode,
+		Status:     status,
+		Body:       r.body,
+		URL:        u,
+	}
+}
@@ -0,0 +1,61 @@
+package rq
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+)
+
+// defaultFingerprintHeaders are the headers included in a fingerprint by default
+var defaultFingerprintHeaders = []string{"Accept", "Content-Type"}
+
+// Fingerprint returns a stable SHA-256 hex key identifying the request, for
+// caching and deduplication. It covers the method, the URL with scheme and
+// host lower-cased and the query sorted, the given headers and the body.
+// Without headers, Accept and Content-Type are used. Header names are
+// case-insensitive and missing headers are skipped.
+//
+// A body set with Body is read into memory so it can still be sent.
+func (r *Request) Fingerprint(headers ...string) string {
+	if len(headers) == 0 {
+		headers = defaultFingerprintHeaders
+	}
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%s\n%s\n", strings.ToUpper(r.method), r.canonicalURL())
+
+	header := r.requestHeader()
+	names := make([]string, len(headers))
+	for i, name := range headers {
+		names[i] = http.CanonicalHeaderKey(name)
+	}
+	slices.Sort(names)
+	for _, name := range slices.Compact(names) {
+		if values := header.Values(name); len(values) > 0 {
+			fmt.Fprintf(h, "%s:%s\n", strings.ToLower(name), strings.Join(values, ","))
+		}
+	}
+
+	h.Write([]byte("\n"))
+	h.Write(r.bufferedBody())
+
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// canonicalURL returns the URL as it will be sent, in a normalized form
+func (r *Request) canonicalURL() string {
+	u, err := r.requestURL()
+	if err != nil {
+		return r.url
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	u.Host = strings.ToLower(u.Host)
+	u.RawQuery = u.Query().Encode()
+	u.Fragment = ""
+
+	return u.String()
+}
@@ -0,0 +1,83 @@
+package rq
+
+import (
+	"io"
+	"strings"
+	"testing"
+)
+
+func TestFingerprint(t *testing.T) {
+	base := func() *Request {
+		return Post("https://api.example.com/items?b=2&a=1").
+			Header("Content-Type", "application/json").
+			BodyString(`{"id":1}`)
+	}
+
+	tests := map[string]struct {
+		other    *Request
+		headers  []string
+		wantSame bool
+	}{
+		"identical": {
+			other:    base(),
+			wantSame: true,
+		},
+		"query order and host case": {
+			other: Post("https://API.example.com/items?a=1&b=2").
+				Header("Content-Type", "application/json").
+				BodyString(`{"id":1}`),
+			wantSame: true,
+		},
+		"ignored header": {
+			other:    base().Header("X-Request-ID", "abc"),
+			wantSame: true,
+		},
+		"configured header": {
+			other:    base().Header("X-Tenant", "a"),
+			headers:  []string{"x-tenant"},
+			wantSame: false,
+		},
+		"different method": {
+			other:    base().Method("PUT"),
+			wantSame: false,
+		},
+		"different body": {
+			other:    base().BodyString(`{"id":2}`),
+			wantSame: false,
+		},
+		"different query": {
+			other:    base().QueryParam("a", "2"),
+			wantSame: false,
+		},
+		"different content type": {
+			other:    base().Header("Content-Type", "text/plain"),
+			wantSame: false,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got := base().Fingerprint(tt.headers...) == tt.other.Fingerprint(tt.headers...)
+			if got != tt.wantSame {
+				t.Errorf("want same fingerprint %v, got %v", tt.wantSame, got)
+			}
+		})
+	}
+}
+
+func TestFingerprintReaderBody(t *testing.T) {
+	r := Post("https://api.example.com").Body(strings.NewReader("payload"))
+
+	first := r.Fingerprint()
+	if second := r.Fingerprint(); first != second {
+		t.Errorf("want stable fingerprint, got %q and %q", first, second)
+	}
+	if first != Post("https://api.example.com").BodyString("payload").Fingerprint() {
+		t.Error("want reader body to fingerprint like the same string body")
+	}
+
+	data, _ := io.ReadAll(r.body)
+	if string(data) != "payload" {
+		t.Errorf("want body to still be readable, got %q", data)
+	}
+}
@@ -2,4 +2,7 @@ module github.com/k64z/rq
 
 go 1.24.3
 
-require golang.org/x/net v0.43.0
+require (
+	golang.org/x/net v0.43.0
+	golang.org/x/time v0.13.0
+)
@@ -0,0 +1,112 @@
+package rq
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// graphQLRequest is the standard GraphQL POST body
+type graphQLRequest struct {
+	Query         string         `json:"query"`
+	Variables     map[string]any `json:"variables,omitempty"`
+	OperationName string         `json:"operationName,omitempty"`
+}
+
+// GraphQLError is a single entry of the errors array of a GraphQL response
+type GraphQLError struct {
+	Message   string `json:"message"`
+	Locations []struct {
+		Line   int `json:"line"`
+		Column int `json:"column"`
+	} `json:"locations,omitempty"`
+	Path       []any          `json:"path,omitempty"`
+	Extensions map[string]any `json:"extensions,omitempty"`
+}
+
+// Error implements the error interface
+func (e GraphQLError) Error() string {
+	if len(e.Path) == 0 {
+		return e.Message
+	}
+
+	path := make([]string, len(e.Path))
+	for i, p := range e.Path {
+		path[i] = fmt.Sprint(p)
+	}
+	return fmt.Sprintf("%s (path %s)", e.Message, strings.Join(path, "."))
+}
+
+// GraphQLErrors is the errors array of a GraphQL response
+type GraphQLErrors []GraphQLError
+
+// Error implements the error interface
+func (e GraphQLErrors) Error() string {
+	if len(e) == 1 {
+		return "graphql: " + e[0].Error()
+	}
+
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("graphql: %d errors: %s", len(e), strings.Join(msgs, "; "))
+}
+
+// BodyGraphQL creates a new request with a GraphQL body
+func BodyGraphQL(query string, variables map[string]any) *Request {
+	return New().BodyGraphQL(query, variables)
+}
+
+// BodyGraphQL sets the request body to a GraphQL query with its variables
+func (r *Request) BodyGraphQL(query string, variables map[string]any) *Request {
+	return r.BodyGraphQLOperation(query, variables, "")
+}
+
+// BodyGraphQLOperation creates a new request with a GraphQL body
+// selecting operationName
+func BodyGraphQLOperation(query string, variables map[string]any, operationName string) *Request {
+	return New().BodyGraphQLOperation(query, variables, operationName)
+}
+
+// BodyGraphQLOperation sets the request body to a GraphQL query, selecting
+// operationName when the document defines several operations
+func (r *Request) BodyGraphQLOperation(query string, variables map[string]any, operationName string) *Request {
+	return r.BodyJSON(graphQLRequest{
+		Query:         query,
+		Variables:     variables,
+		OperationName: operationName,
+	})
+}
+
+// GraphQL decodes the data field of a GraphQL response into v. If the
+// response carries an errors array it is returned as GraphQLErrors, after
+// any partial data has been decoded.
+func (r *Response) GraphQL(v any) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	var envelope struct {
+		Data   json.RawMessage `json:"data"`
+		Errors GraphQLErrors   `json:"errors"`
+	}
+	if err := json.Unmarshal(r.body, &envelope); err != nil {
+		if httpErr := r.AsHTTPError(); httpErr != nil {
+			return httpErr
+		}
+		return fmt.Errorf("decode GraphQL response: %w", err)
+	}
+
+	if v != nil && len(envelope.Data) > 0 && string(envelope.Data) != "null" {
+		if err := json.Unmarshal(envelope.Data, v); err != nil {
+			return fmt.Errorf("decode GraphQL data: %w", err)
+		}
+	}
+
+	if len(envelope.Errors) > 0 {
+		return envelope.Errors
+	}
+
+	return nil
+}
@@ -0,0 +1,136 @@
+package rq
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBodyGraphQL(t *testing.T) {
+	var got graphQLRequest
+	var contentType string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		got = graphQLRequest{}
+		json.NewDecoder(r.Body).Decode(&got)
+	}))
+	defer srv.Close()
+
+	tests := map[string]struct {
+		req  *Request
+		want graphQLRequest
+	}{
+		"query with variables": {
+			req: Post(srv.URL).BodyGraphQL("query($id: ID!) { user(id: $id) { name } }", map[string]any{"id": "1"}),
+			want: graphQLRequest{
+				Query:     "query($id: ID!) { user(id: $id) { name } }",
+				Variables: map[string]any{"id": "1"},
+			},
+		},
+		"operation name": {
+			req: BodyGraphQLOperation("query A { a } query B { b }", nil, "B").Method(http.MethodPost).URL(srv.URL),
+			want: graphQLRequest{
+				Query:         "query A { a } query B { b }",
+				OperationName: "B",
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if resp := tt.req.Do(); resp.Error() != nil {
+				t.Fatal(resp.Error())
+			}
+
+			if contentType != "application/json" {
+				t.Errorf("want Content-Type application/json, got %q", contentType)
+			}
+			if got.Query != tt.want.Query || got.OperationName != tt.want.OperationName {
+				t.Errorf("want %+v, got %+v", tt.want, got)
+			}
+			if len(got.Variables) != len(tt.want.Variables) || got.Variables["id"] != tt.want.Variables["id"] {
+				t.Errorf("want variables %v, got %v", tt.want.Variables, got.Variables)
+			}
+		})
+	}
+}
+
+func TestResponseGraphQL(t *testing.T) {
+	type user struct {
+		User struct {
+			Name string `json:"name"`
+		} `json:"user"`
+	}
+
+	tests := map[string]struct {
+		status   int
+		body     string
+		wantName string
+		wantErr  string
+	}{
+		"data": {
+			body:     `{"data":{"user":{"name":"john"}}}`,
+			wantName: "john",
+		},
+		"partial data with errors": {
+			body:     `{"data":{"user":{"name":"john"}},"errors":[{"message":"friends unavailable","path":["user","friends",0]}]}`,
+			wantName: "john",
+			wantErr:  "graphql: friends unavailable (path user.friends.0)",
+		},
+		"multiple errors": {
+			body:    `{"data":null,"errors":[{"message":"a"},{"message":"b"}]}`,
+			wantErr: "graphql: 2 errors: a; b",
+		},
+		"non JSON error status": {
+			status:  http.StatusBadGateway,
+			body:    "bad gateway",
+			wantErr: "http error: 502 Bad Gateway",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.status != 0 {
+					w.WriteHeader(tt.status)
+				}
+				w.Write([]byte(tt.body))
+			}))
+			defer srv.Close()
+
+			var data user
+			err := Post(srv.URL).BodyGraphQL("{ user { name } }", nil).Do().GraphQL(&data)
+
+			if data.User.Name != tt.wantName {
+				t.Errorf("want name %q, got %q", tt.wantName, data.User.Name)
+			}
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("want no error, got %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.HasPrefix(err.Error(), tt.wantErr) {
+				t.Errorf("want error %q, got %v", tt.wantErr, err)
+			}
+		})
+	}
+
+	t.Run("errors are inspectable", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write([]byte(`{"errors":[{"message":"denied","extensions":{"code":"FORBIDDEN"}}]}`))
+		}))
+		defer srv.Close()
+
+		err := Post(srv.URL).BodyGraphQL("{ secret }", nil).Do().GraphQL(nil)
+
+		var gqlErrs GraphQLErrors
+		if !errors.As(err, &gqlErrs) || gqlErrs[0].Extensions["code"] != "FORBIDDEN" {
+			t.Errorf("want GraphQLErrors with code FORBIDDEN, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,198 @@
+package rq
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+)
+
+// harEntry is a single entry of a HAR 1.2 log
+type harEntry struct {
+	StartedDateTime string      `json:"startedDateTime"`
+	Time            float64     `json:"time"`
+	Request         harRequest  `json:"request"`
+	Response        harResponse `json:"response"`
+	Cache           struct{}    `json:"cache"`
+	Timings         harTimings  `json:"timings"`
+}
+
+type harRequest struct {
+	Method      string         `json:"method"`
+	URL         string         `json:"url"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	QueryString []harNameValue `json:"queryString"`
+	PostData    *harPostData   `json:"postData,omitempty"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harResponse struct {
+	Status      int            `json:"status"`
+	StatusText  string         `json:"statusText"`
+	HTTPVersion string         `json:"httpVersion"`
+	Cookies     []harNameValue `json:"cookies"`
+	Headers     []harNameValue `json:"headers"`
+	Content     harContent     `json:"content"`
+	RedirectURL string         `json:"redirectURL"`
+	HeadersSize int            `json:"headersSize"`
+	BodySize    int            `json:"bodySize"`
+}
+
+type harNameValue struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+type harPostData struct {
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type harContent struct {
+	Size     int    `json:"size"`
+	MimeType string `json:"mimeType"`
+	Text     string `json:"text"`
+	Encoding string `json:"encoding,omitempty"`
+}
+
+type harTimings struct {
+	Blocked float64 `json:"blocked"`
+	DNS     float64 `json:"dns"`
+	Connect float64 `json:"connect"`
+	Send    float64 `json:"send"`
+	Wait    float64 `json:"wait"`
+	Receive float64 `json:"receive"`
+	SSL     float64 `json:"ssl"`
+}
+
+// ToHAR returns the request and response as a HAR 1.2 entry in JSON, for
+// tools that import HTTP Archive files. Timings come from Response.Timing.
+// Binary bodies are base64-encoded. The request body is only included when
+// it was set in memory, e.g. with BodyString or BodyJSON, not with Body.
+func (r *Response) ToHAR() ([]byte, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if r.Response == nil || r.Request == nil {
+		return nil, errors.New("har: response has no request")
+	}
+	if r.stream {
+		return nil, errors.New("har: streamed response body is not available")
+	}
+
+	req := r.Request
+	timing := r.timing
+
+	started := timing.Start
+	if started.IsZero() {
+		started = time.Now()
+	}
+
+	harReq := harRequest{
+		Method:      req.Method,
+		URL:         req.URL.String(),
+		HTTPVersion: req.Proto,
+		Cookies:     harCookies(req.Cookies()),
+		Headers:     harPairs(req.Header),
+		QueryString: harPairs(req.URL.Query()),
+		HeadersSize: -1,
+		BodySize:    len(r.requestBody),
+	}
+	if harReq.HTTPVersion == "" {
+		harReq.HTTPVersion = "HTTP/1.1"
+	}
+	if r.requestBody != nil {
+		text, encoding := harText(r.requestBody)
+		harReq.PostData = &harPostData{
+			MimeType: req.Header.Get("Content-Type"),
+			Text:     text,
+			Encoding: encoding,
+		}
+	}
+
+	text, encoding := harText(r.body)
+	harResp := harResponse{
+		Status:      r.StatusCode,
+		StatusText:  http.StatusText(r.StatusCode),
+		HTTPVersion: r.Proto,
+		Cookies:     harCookies(r.Cookies()),
+		Headers:     harPairs(r.Header),
+		Content: harContent{
+			Size:     len(r.body),
+			MimeType: r.Header.Get("Content-Type"),
+			Text:     text,
+			Encoding: encoding,
+		},
+		RedirectURL: r.Header.Get("Location"),
+		HeadersSize: -1,
+		BodySize:    len(r.body),
+	}
+
+	timings := harTimings{
+		Blocked: -1,
+		DNS:     harMillis(timing.DNSLookup),
+		Connect: harMillis(timing.Connect + timing.TLSHandshake),
+		SSL:     harMillis(timing.TLSHandshake),
+		Wait:    harMillis(max(timing.TimeToFirstByte-timing.DNSLookup-timing.Connect-timing.TLSHandshake, 0)),
+		Receive: harMillis(max(timing.Total-timing.TimeToFirstByte, 0)),
+	}
+
+	entry := harEntry{
+		StartedDateTime: started.Format(time.RFC3339Nano),
+		Time:            timings.DNS + timings.Connect + timings.Send + timings.Wait + timings.Receive,
+		Request:         harReq,
+		Response:        harResp,
+		Timings:         timings,
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return nil, fmt.Errorf("har: %w", err)
+	}
+
+	return data, nil
+}
+
+// harPairs flattens headers or query values into sorted name/value pairs
+func harPairs(values map[string][]string) []harNameValue {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+
+	pairs := []harNameValue{}
+	for _, name := range names {
+		for _, v := range values[name] {
+			pairs = append(pairs, harNameValue{Name: name, Value: v})
+		}
+	}
+	return pairs
+}
+
+func harCookies(cookies []*http.Cookie) []harNameValue {
+	pairs := []harNameValue{}
+	for _, c := range cookies {
+		pairs = append(pairs, harNameValue{Name: c.Name, Value: c.Value})
+	}
+	return pairs
+}
+
+// harText returns data as text, base64-encoding binary content
+func harText(data []byte) (text, encoding string) {
+	if isBinary(data) {
+		return base64.StdEncoding.EncodeToString(data), "base64"
+	}
+	return string(data), ""
+}
+
+func harMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
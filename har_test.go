@@ -0,0 +1,92 @@
+package rq
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestToHAR(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/binary":
+			w.Header().Set("Content-Type", "application/octet-stream")
+			w.Write([]byte{0x00, 0x01, 0xff})
+		default:
+			w.Header().Set("Content-Type", "application/json")
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id":1}`))
+		}
+	}))
+	defer srv.Close()
+
+	t.Run("text bodies", func(t *testing.T) {
+		resp := Post(srv.URL+"/users").
+			QueryParam("debug", "1").
+			BodyJSON(map[string]string{"name": "john"}).
+			Do()
+
+		data, err := resp.ToHAR()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var entry harEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			t.Fatalf("want valid JSON, got %v", err)
+		}
+
+		if entry.Request.Method != http.MethodPost {
+			t.Errorf("want method POST, got %q", entry.Request.Method)
+		}
+		if len(entry.Request.QueryString) != 1 || entry.Request.QueryString[0] != (harNameValue{"debug", "1"}) {
+			t.Errorf("want query [debug=1], got %v", entry.Request.QueryString)
+		}
+		if entry.Request.PostData == nil || entry.Request.PostData.Text != `{"name":"john"}` {
+			t.Errorf("want postData with JSON body, got %+v", entry.Request.PostData)
+		}
+		if entry.Response.Status != http.StatusCreated {
+			t.Errorf("want status 201, got %d", entry.Response.Status)
+		}
+		if entry.Response.Content.Text != `{"id":1}` || entry.Response.Content.Encoding != "" {
+			t.Errorf("want plain response content, got %+v", entry.Response.Content)
+		}
+		if entry.Response.Content.MimeType != "application/json" {
+			t.Errorf("want mimeType application/json, got %q", entry.Response.Content.MimeType)
+		}
+		if entry.Time <= 0 {
+			t.Errorf("want positive total time, got %v", entry.Time)
+		}
+		if entry.StartedDateTime == "" {
+			t.Error("want startedDateTime to be set")
+		}
+	})
+
+	t.Run("binary body", func(t *testing.T) {
+		data, err := Get(srv.URL + "/binary").Do().ToHAR()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var entry harEntry
+		if err := json.Unmarshal(data, &entry); err != nil {
+			t.Fatal(err)
+		}
+
+		want := base64.StdEncoding.EncodeToString([]byte{0x00, 0x01, 0xff})
+		if entry.Response.Content.Encoding != "base64" || entry.Response.Content.Text != want {
+			t.Errorf("want base64 content %q, got %+v", want, entry.Response.Content)
+		}
+		if entry.Request.PostData != nil {
+			t.Errorf("want no postData for GET, got %+v", entry.Request.PostData)
+		}
+	})
+
+	t.Run("failed request", func(t *testing.T) {
+		if _, err := New().Do().ToHAR(); err == nil {
+			t.Error("want error for failed request, got nil")
+		}
+	})
+}
@@ -0,0 +1,40 @@
+package rq
+
+import "net/http"
+
+// OnRequest creates a new request with a hook run before it is sent
+func OnRequest(fn func(*http.Request)) *Request {
+	return New().OnRequest(fn)
+}
+
+// OnRequest registers fn to observe the outgoing *http.Request right before
+// it is sent. Hooks run in registration order and cannot abort the request.
+func (r *Request) OnRequest(fn func(*http.Request)) *Request {
+	if r.err != nil {
+		return r
+	}
+	r.beforeSend = append(r.beforeSend, func(req *http.Request) error {
+		fn(req)
+		return nil
+	})
+	return r
+}
+
+// OnResponse creates a new request with a hook run after the response arrives
+func OnResponse(fn func(*Response)) *Request {
+	return New().OnResponse(fn)
+}
+
+// OnResponse registers fn to observe the response right after it has been
+// received, before validators run. It is also called when the request fails,
+// with the failure available from Response.Error. Hooks run in registration
+// order.
+func (r *Request) OnResponse(fn func(*Response)) *Request {
+	if r.err != nil {
+		return r
+	}
+	r.afterResponse = append(r.afterResponse, func(_ *http.Request, resp *Response) {
+		fn(resp)
+	})
+	return r
+}
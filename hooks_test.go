@@ -0,0 +1,51 @@
+package rq
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestHooks(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	defer srv.Close()
+
+	var calls []string
+	resp := Get(srv.URL).
+		Header("X-Test", "1").
+		OnRequest(func(req *http.Request) {
+			calls = append(calls, "request 1 "+req.Header.Get("X-Test"))
+		}).
+		OnRequest(func(req *http.Request) {
+			calls = append(calls, "request 2")
+		}).
+		OnResponse(func(resp *Response) {
+			calls = append(calls, "response 1 "+resp.Status)
+		}).
+		OnResponse(func(resp *Response) {
+			calls = append(calls, "response 2")
+		}).
+		Do()
+	if resp.Error() != nil {
+		t.Fatal(resp.Error())
+	}
+
+	want := "request 1 1,request 2,response 1 418 I'm a teapot,response 2"
+	if got := strings.Join(calls, ","); got != want {
+		t.Errorf("want calls %q, got %q", want, got)
+	}
+}
+
+func TestOnResponseOnFailure(t *testing.T) {
+	var got error
+	Get("http://127.0.0.1:1").OnResponse(func(resp *Response) {
+		got = resp.Error()
+	}).Do()
+
+	if got == nil {
+		t.Error("want OnResponse to see the request error, got nil")
+	}
+}
@@ -0,0 +1,60 @@
+package rq
+
+import (
+	"crypto/tls"
+	"net/http"
+	"slices"
+)
+
+// ForceHTTP1 creates a new request that only speaks HTTP/1.1
+func ForceHTTP1() *Request {
+	return New().ForceHTTP1()
+}
+
+// ForceHTTP1 disables HTTP/2 so that every connection uses HTTP/1.1, even
+// when the server offers h2 during the TLS handshake. Proxy and TLS settings
+// of the current transport are kept.
+func (r *Request) ForceHTTP1() *Request {
+	return r.updateTransport("force HTTP/1.1", func(t *http.Transport) {
+		protocols := new(http.Protocols)
+		protocols.SetHTTP1(true)
+
+		t.Protocols = protocols
+		t.ForceAttemptHTTP2 = false
+		t.TLSNextProto = make(map[string]func(string, *tls.Conn) http.RoundTripper)
+		stripH2(t)
+	})
+}
+
+// stripH2 removes h2 from the ALPN protocols of a transport limited to
+// HTTP/1, so a TLS config applied before or after ForceHTTP1 cannot bring
+// HTTP/2 back
+func stripH2(t *http.Transport) {
+	if t.Protocols == nil || t.Protocols.HTTP2() || t.TLSClientConfig == nil {
+		return
+	}
+	if slices.Contains(t.TLSClientConfig.NextProtos, "h2") {
+		t.TLSClientConfig.NextProtos = slices.DeleteFunc(slices.Clone(t.TLSClientConfig.NextProtos), func(p string) bool {
+			return p == "h2"
+		})
+	}
+}
+
+// EnableH2C creates a new request that uses cleartext HTTP/2
+func EnableH2C() *Request {
+	return New().EnableH2C()
+}
+
+// EnableH2C makes http:// requests use HTTP/2 without TLS (prior knowledge
+// h2c), for servers known to support it. https:// requests keep negotiating
+// HTTP/2 over TLS. Servers that only speak HTTP/1.1 will fail the request.
+func (r *Request) EnableH2C() *Request {
+	return r.updateTransport("enable h2c", func(t *http.Transport) {
+		protocols := new(http.Protocols)
+		protocols.SetHTTP2(true)
+		protocols.SetUnencryptedHTTP2(true)
+
+		t.Protocols = protocols
+		t.TLSNextProto = nil
+	})
+}
@@ -0,0 +1,82 @@
+package rq
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestForceHTTP1(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Proto))
+	}))
+	srv.EnableHTTP2 = true
+	srv.StartTLS()
+	defer srv.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+
+	tests := map[string]struct {
+		req  *Request
+		want string
+	}{
+		"default negotiates h2": {
+			req:  Get(srv.URL).RootCAs(pool),
+			want: "HTTP/2.0",
+		},
+		"ForceHTTP1": {
+			req:  Get(srv.URL).RootCAs(pool).ForceHTTP1(),
+			want: "HTTP/1.1",
+		},
+		"ForceHTTP1 after TLS config offering h2": {
+			req:  Get(srv.URL).TLSConfig(&tls.Config{RootCAs: pool, NextProtos: []string{"h2", "http/1.1"}}).ForceHTTP1(),
+			want: "HTTP/1.1",
+		},
+		"ForceHTTP1 before TLS config offering h2": {
+			req:  Get(srv.URL).ForceHTTP1().TLSConfig(&tls.Config{RootCAs: pool, NextProtos: []string{"h2", "http/1.1"}}),
+			want: "HTTP/1.1",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := tt.req.Do().String()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("want %s, got %s", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestEnableH2C(t *testing.T) {
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Proto))
+	}))
+	srv.Config.Protocols = new(http.Protocols)
+	srv.Config.Protocols.SetHTTP1(true)
+	srv.Config.Protocols.SetUnencryptedHTTP2(true)
+	srv.Start()
+	defer srv.Close()
+
+	got, err := Get(srv.URL).EnableH2C().Do().String()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "HTTP/2.0" {
+		t.Errorf("want HTTP/2.0, got %s", got)
+	}
+
+	got, err = Get(srv.URL).EnableH2C().ForceHTTP1().Do().String()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got != "HTTP/1.1" {
+		t.Errorf("want HTTP/1.1 after ForceHTTP1, got %s", got)
+	}
+}
@@ -3,12 +3,15 @@ package rq
 import (
 	"bytes"
 	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
 	"net/http/httputil"
 	"os"
+	"slices"
+	"strings"
 )
 
 // RequestInterceptor allows inspection/modification of http.Request
@@ -60,14 +63,42 @@ func (t *InterceptorTransport) RoundTrip(req *http.Request) (*http.Response, err
 	return resp, nil
 }
 
-// DumpTransport creates a transport that dumps requests and responses
+// redacted replaces masked header values and body fields in dumps
+const redacted = "[REDACTED]"
+
+// defaultRedactHeaders are masked in dumps unless DumpOptions says otherwise
+var defaultRedactHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+
+// DumpOptions controls what is masked in request and response dumps
+type DumpOptions struct {
+	// RedactHeaders lists headers whose values are masked. Nil masks
+	// Authorization, Cookie and Set-Cookie; an empty slice masks nothing.
+	RedactHeaders []string
+
+	// RedactBodyFields lists JSON object keys whose values are masked at
+	// any depth. Bodies that are not JSON are dumped unchanged.
+	RedactBodyFields []string
+}
+
+// DumpTransport creates a transport that dumps requests and responses,
+// masking the Authorization, Cookie and Set-Cookie headers
 func DumpTransport(base http.RoundTripper, logger *log.Logger) *InterceptorTransport {
+	return DumpTransportWithOptions(base, logger, DumpOptions{})
+}
+
+// DumpTransportWithOptions creates a transport that dumps requests and
+// responses, masking headers and JSON body fields according to opts.
+// Only the dumps are masked; what is sent and received is unchanged.
+func DumpTransportWithOptions(base http.RoundTripper, logger *log.Logger, opts DumpOptions) *InterceptorTransport {
 	if base == nil {
 		base = http.DefaultTransport
 	}
 	if logger == nil {
 		logger = log.New(os.Stdout, "[HTTP] ", log.LstdFlags)
 	}
+	if opts.RedactHeaders == nil {
+		opts.RedactHeaders = defaultRedactHeaders
+	}
 
 	dumpWrapper := RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
 		// Preserve the original body by reading it into memory
@@ -88,13 +119,16 @@ func DumpTransport(base http.RoundTripper, logger *log.Logger) *InterceptorTrans
 		// Making the actual request may modify headers and consume body
 		resp, err := base.RoundTrip(req)
 
-		// Restore the body again for dumping the modified request
+		// Dump a masked copy of the request regardless of success or failure
+		dumpReq := req.Clone(req.Context())
+		dumpReq.Header = opts.redactHeader(req.Header)
 		if bodyBytes != nil {
-			req.Body = io.NopCloser(bytes.NewReader(bodyBytes))
+			body := opts.redactBody(bodyBytes)
+			dumpReq.Body = io.NopCloser(bytes.NewReader(body))
+			dumpReq.ContentLength = int64(len(body))
 		}
 
-		// Dump the request regardless of success or failure
-		dump, dumpErr := httputil.DumpRequestOut(req, true)
+		dump, dumpErr := httputil.DumpRequestOut(dumpReq, true)
 		if dumpErr != nil {
 			logger.Printf("Failed to dump request: %v", dumpErr)
 		} else {
@@ -107,7 +141,23 @@ func DumpTransport(base http.RoundTripper, logger *log.Logger) *InterceptorTrans
 	return &InterceptorTransport{
 		Base: dumpWrapper,
 		ResponseInterceptor: func(ctx context.Context, resp *http.Response) error {
-			dump, err := httputil.DumpResponse(resp, true)
+			body, err := io.ReadAll(resp.Body)
+			_ = resp.Body.Close()
+			resp.Body = io.NopCloser(bytes.NewReader(body))
+			if err != nil {
+				logger.Printf("Failed to dump response: %v", err)
+				return nil
+			}
+
+			dumpResp := *resp
+			dumpResp.Header = opts.redactHeader(resp.Header)
+			if masked := opts.redactBody(body); !bytes.Equal(masked, body) {
+				body = masked
+				dumpResp.ContentLength = int64(len(body))
+			}
+			dumpResp.Body = io.NopCloser(bytes.NewReader(body))
+
+			dump, err := httputil.DumpResponse(&dumpResp, true)
 			if err != nil {
 				logger.Printf("Failed to dump response: %v", err)
 				return nil
@@ -118,3 +168,68 @@ func DumpTransport(base http.RoundTripper, logger *log.Logger) *InterceptorTrans
 		},
 	}
 }
+
+// redactHeader returns a copy of header with the configured values masked
+func (o DumpOptions) redactHeader(header http.Header) http.Header {
+	header = header.Clone()
+	for _, name := range o.RedactHeaders {
+		if values := header.Values(name); len(values) > 0 {
+			masked := make([]string, len(values))
+			for i := range masked {
+				masked[i] = redacted
+			}
+			header[http.CanonicalHeaderKey(name)] = masked
+		}
+	}
+	return header
+}
+
+// redactBody masks the configured fields of a JSON body. Other bodies,
+// and JSON bodies without such fields, are returned unchanged.
+func (o DumpOptions) redactBody(body []byte) []byte {
+	if len(o.RedactBodyFields) == 0 || len(body) == 0 {
+		return body
+	}
+
+	value, err := decodeJSONValue(body)
+	if err != nil {
+		return body
+	}
+
+	if !redactJSONFields(value, o.RedactBodyFields) {
+		return body
+	}
+
+	masked, err := json.Marshal(value)
+	if err != nil {
+		return body
+	}
+	return masked
+}
+
+// redactJSONFields masks matching object keys in place, reporting whether any matched
+func redactJSONFields(value any, fields []string) bool {
+	var changed bool
+
+	switch v := value.(type) {
+	case map[string]any:
+		for key, child := range v {
+			if slices.ContainsFunc(fields, func(f string) bool { return strings.EqualFold(f, key) }) {
+				v[key] = redacted
+				changed = true
+				continue
+			}
+			if redactJSONFields(child, fields) {
+				changed = true
+			}
+		}
+	case []any:
+		for _, child := range v {
+			if redactJSONFields(child, fields) {
+				changed = true
+			}
+		}
+	}
+
+	return changed
+}
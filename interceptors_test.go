@@ -289,3 +289,69 @@ func TestDumpTransportWithMultipartBody(t *testing.T) {
 		t.Error("want multipart value in request dump")
 	}
 }
+
+func TestDumpMiddlewareRedaction(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), "hunter2") {
+			t.Errorf("want the real password to be sent, got %s", body)
+		}
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "secret-session"})
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"user":{"name":"john","token":"secret-token"}}`))
+	}))
+	defer srv.Close()
+
+	tests := map[string]struct {
+		opts        DumpOptions
+		wantHidden  []string
+		wantVisible []string
+	}{
+		"default headers": {
+			wantHidden:  []string{"Bearer abc", "secret-session"},
+			wantVisible: []string{"Authorization: [REDACTED]", "hunter2", "secret-token", "X-Api-Key: key"},
+		},
+		"custom headers and body fields": {
+			opts: DumpOptions{
+				RedactHeaders:    []string{"X-Api-Key"},
+				RedactBodyFields: []string{"password", "token"},
+			},
+			wantHidden:  []string{"hunter2", "secret-token", "X-Api-Key: key"},
+			wantVisible: []string{"Bearer abc", `"password":"[REDACTED]"`, `"name":"john"`},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			var buf bytes.Buffer
+			logger := log.New(&buf, "", 0)
+
+			resp := Post(srv.URL).
+				BearerToken("abc").
+				Header("X-Api-Key", "key").
+				BodyJSON(map[string]string{"user": "john", "password": "hunter2"}).
+				Use(DumpMiddlewareWithOptions(logger, tt.opts)).
+				Do()
+			if resp.Error() != nil {
+				t.Fatal(resp.Error())
+			}
+
+			body, _ := resp.String()
+			if !strings.Contains(body, "secret-token") {
+				t.Errorf("want the real response body, got %s", body)
+			}
+
+			logOutput := buf.String()
+			for _, s := range tt.wantHidden {
+				if strings.Contains(logOutput, s) {
+					t.Errorf("want %q to be redacted, got:\n%s", s, logOutput)
+				}
+			}
+			for _, s := range tt.wantVisible {
+				if !strings.Contains(logOutput, s) {
+					t.Errorf("want %q in dump, got:\n%s", s, logOutput)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package rq
+
+import (
+	"net/http"
+	"net/http/cookiejar"
+)
+
+// CookieJar creates a new request with a cookie jar
+func CookieJar(jar http.CookieJar) *Request {
+	return New().CookieJar(jar)
+}
+
+// CookieJar sets the cookie jar on a copy of the client. Cookies set by
+// responses are stored in the jar and sent with later requests using it.
+// Cookies added with Cookies are sent in addition to the jar's cookies and
+// are not stored in the jar.
+func (r *Request) CookieJar(jar http.CookieJar) *Request {
+	if r.err != nil {
+		return r
+	}
+
+	client := r.cloneClient()
+	client.Jar = jar
+	r.client = client
+	return r
+}
+
+// EnableCookies creates a new request with an in-memory cookie jar
+func EnableCookies() *Request {
+	return New().EnableCookies()
+}
+
+// EnableCookies attaches a new in-memory cookie jar unless the client already
+// has one. Derive follow-up requests with Clone to share the jar, e.g. to
+// log in and then make authenticated requests.
+func (r *Request) EnableCookies() *Request {
+	if r.err != nil {
+		return r
+	}
+	if r.client != nil && r.client.Jar != nil {
+		return r
+	}
+
+	jar, _ := cookiejar.New(nil) // never fails without options
+	return r.CookieJar(jar)
+}
+
+// Cookies parses the cookies set by the response's Set-Cookie headers
+func (r *Response) Cookies() []*http.Cookie {
+	if r.err != nil || r.Response == nil {
+		return nil
+	}
+	return r.Response.Cookies()
+}
+
+// Cookie returns the named cookie set by the response, or
+// http.ErrNoCookie if it was not set
+func (r *Response) Cookie(name string) (*http.Cookie, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	for _, cookie := range r.Cookies() {
+		if cookie.Name == name {
+			return cookie, nil
+		}
+	}
+
+	return nil, http.ErrNoCookie
+}
@@ -0,0 +1,113 @@
+package rq
+
+import (
+	"errors"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestEnableCookies(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/login":
+			http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", Path: "/"})
+		case "/me":
+			session, err := r.Cookie("session")
+			if err != nil {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			extra, _ := r.Cookie("extra")
+			if extra != nil {
+				w.Write([]byte(session.Value + "," + extra.Value))
+				return
+			}
+			w.Write([]byte(session.Value))
+		}
+	}))
+	defer srv.Close()
+
+	base := EnableCookies()
+	if base.Clone().Method(http.MethodPost).URL(srv.URL+"/login").Do().Error() != nil {
+		t.Fatal("login failed")
+	}
+
+	t.Run("jar cookies are sent", func(t *testing.T) {
+		got, err := base.Clone().URL(srv.URL + "/me").Do().String()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != "abc" {
+			t.Errorf("want session abc, got %q", got)
+		}
+	})
+
+	t.Run("explicit cookies are added", func(t *testing.T) {
+		got, err := base.Clone().URL(srv.URL + "/me").Cookies(&http.Cookie{Name: "extra", Value: "1"}).Do().String()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != "abc,1" {
+			t.Errorf("want abc,1, got %q", got)
+		}
+	})
+
+	t.Run("requests without jar", func(t *testing.T) {
+		resp := Get(srv.URL + "/me").Do()
+		if resp.StatusCode != http.StatusUnauthorized {
+			t.Errorf("want status 401, got %d", resp.StatusCode)
+		}
+	})
+}
+
+func TestCookieJar(t *testing.T) {
+	jar, _ := cookiejar.New(nil)
+	shared := &http.Client{}
+
+	r := Client(shared).CookieJar(jar)
+	if r.client.Jar != jar {
+		t.Error("want jar to be set")
+	}
+	if shared.Jar != nil {
+		t.Error("want shared client to be left unchanged")
+	}
+	if r.EnableCookies().client.Jar != jar {
+		t.Error("want EnableCookies to keep the existing jar")
+	}
+}
+
+func TestResponseCookies(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc", HttpOnly: true})
+		http.SetCookie(w, &http.Cookie{Name: "lang", Value: "en"})
+	}))
+	defer srv.Close()
+
+	resp := Get(srv.URL).Do()
+
+	if got := len(resp.Cookies()); got != 2 {
+		t.Errorf("want 2 cookies, got %d", got)
+	}
+
+	session, err := resp.Cookie("session")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if session.Value != "abc" || !session.HttpOnly {
+		t.Errorf("want HttpOnly session=abc, got %v", session)
+	}
+
+	if _, err := resp.Cookie("missing"); !errors.Is(err, http.ErrNoCookie) {
+		t.Errorf("want http.ErrNoCookie, got %v", err)
+	}
+
+	failed := New().Do()
+	if failed.Cookies() != nil {
+		t.Error("want no cookies for failed request")
+	}
+	if _, err := failed.Cookie("session"); err == nil || errors.Is(err, http.ErrNoCookie) {
+		t.Errorf("want request error, got %v", err)
+	}
+}
@@ -0,0 +1,143 @@
+package rq
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// jsonPathStep is a single field or index selector
+type jsonPathStep struct {
+	key     string
+	index   int
+	isIndex bool
+}
+
+// JSONPath evaluates a JSONPath expression against the JSON body.
+// Supported selectors are the root ($), child fields (.name or ['name'])
+// and array indexes ([0], negative indexes count from the end).
+func (r *Response) JSONPath(path string) (any, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+
+	steps, err := parseJSONPath(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var doc any
+	if err := json.Unmarshal(r.body, &doc); err != nil {
+		return nil, fmt.Errorf("decode JSON: %w", err)
+	}
+
+	return evalJSONPath(doc, path, steps)
+}
+
+// parseJSONPath splits a JSONPath expression into steps
+func parseJSONPath(path string) ([]jsonPathStep, error) {
+	if !strings.HasPrefix(path, "$") {
+		return nil, fmt.Errorf("invalid JSONPath %q: must start with $", path)
+	}
+
+	var steps []jsonPathStep
+	rest := path[1:]
+
+	for rest != "" {
+		switch rest[0] {
+		case '.':
+			rest = rest[1:]
+			end := strings.IndexAny(rest, ".[")
+			if end < 0 {
+				end = len(rest)
+			}
+			if end == 0 {
+				return nil, fmt.Errorf("invalid JSONPath %q: empty field name", path)
+			}
+			steps = append(steps, jsonPathStep{key: rest[:end]})
+			rest = rest[end:]
+		case '[':
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				return nil, fmt.Errorf("invalid JSONPath %q: unclosed bracket", path)
+			}
+			selector := rest[1:end]
+			rest = rest[end+1:]
+
+			if len(selector) >= 2 && (selector[0] == '\'' || selector[0] == '"') && selector[len(selector)-1] == selector[0] {
+				steps = append(steps, jsonPathStep{key: selector[1 : len(selector)-1]})
+				continue
+			}
+
+			index, err := strconv.Atoi(selector)
+			if err != nil {
+				return nil, fmt.Errorf("invalid JSONPath %q: bad index %q", path, selector)
+			}
+			steps = append(steps, jsonPathStep{index: index, isIndex: true})
+		default:
+			return nil, fmt.Errorf("invalid JSONPath %q: unexpected %q", path, rest[0])
+		}
+	}
+
+	return steps, nil
+}
+
+// evalJSONPath walks doc following steps
+func evalJSONPath(doc any, path string, steps []jsonPathStep) (any, error) {
+	current := doc
+
+	for _, step := range steps {
+		if step.isIndex {
+			arr, ok := current.([]any)
+			if !ok {
+				return nil, fmt.Errorf("JSONPath %q: cannot index %T", path, current)
+			}
+
+			index := step.index
+			if index < 0 {
+				index += len(arr)
+			}
+			if index < 0 || index >= len(arr) {
+				return nil, fmt.Errorf("JSONPath %q: index %d out of range", path, step.index)
+			}
+
+			current = arr[index]
+			continue
+		}
+
+		obj, ok := current.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("JSONPath %q: cannot select field %q from %T", path, step.key, current)
+		}
+
+		value, ok := obj[step.key]
+		if !ok {
+			return nil, fmt.Errorf("JSONPath %q: field %q not found", path, step.key)
+		}
+
+		current = value
+	}
+
+	return current, nil
+}
+
+// formatJSONValue formats a decoded JSON value for comparison with a string
+func formatJSONValue(v any) string {
+	switch val := v.(type) {
+	case string:
+		return val
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case nil:
+		return "null"
+	case bool:
+		return strconv.FormatBool(val)
+	default:
+		data, err := json.Marshal(val)
+		if err != nil {
+			return fmt.Sprint(val)
+		}
+		return string(data)
+	}
+}
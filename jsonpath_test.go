@@ -0,0 +1,94 @@
+package rq
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestResponseJSONPath(t *testing.T) {
+	resp := &Response{body: []byte(`{
+		"data": {
+			"items": [{"id": 1, "name": "first"}, {"id": 2, "name": "second"}],
+			"total": 2,
+			"next": null,
+			"weird key": true
+		}
+	}`)}
+
+	tests := map[string]struct {
+		path    string
+		want    any
+		wantErr bool
+	}{
+		"root field": {
+			path: "$.data.total",
+			want: float64(2),
+		},
+		"array index": {
+			path: "$.data.items[0].id",
+			want: float64(1),
+		},
+		"negative index": {
+			path: "$.data.items[-1].name",
+			want: "second",
+		},
+		"bracket key": {
+			path: "$['data']['weird key']",
+			want: true,
+		},
+		"null value": {
+			path: "$.data.next",
+			want: nil,
+		},
+		"object value": {
+			path: "$.data.items[1]",
+			want: map[string]any{"id": float64(2), "name": "second"},
+		},
+		"missing field": {
+			path:    "$.data.missing",
+			wantErr: true,
+		},
+		"index out of range": {
+			path:    "$.data.items[5]",
+			wantErr: true,
+		},
+		"index on object": {
+			path:    "$.data[0]",
+			wantErr: true,
+		},
+		"missing root": {
+			path:    "data.total",
+			wantErr: true,
+		},
+		"unclosed bracket": {
+			path:    "$.data.items[0",
+			wantErr: true,
+		},
+		"bad index": {
+			path:    "$.data.items[x]",
+			wantErr: true,
+		},
+		"empty field": {
+			path:    "$.data..total",
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := resp.JSONPath(tt.path)
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("want error, got %v", got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("want %v, got %v", tt.want, got)
+			}
+		})
+	}
+}
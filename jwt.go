@@ -0,0 +1,207 @@
+package rq
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"maps"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// defaultJWTTTL is the token lifetime used when JWTAuth.TTL is zero
+const defaultJWTTTL = 5 * time.Minute
+
+// JWTAuth is an AuthProvider that mints signed JWTs and sends them as
+// Bearer tokens. Tokens are cached and regenerated shortly before they
+// expire. It is safe to share one provider across goroutines.
+//
+// Key selects the algorithm: a []byte signs with HS256, an *rsa.PrivateKey
+// with RS256 and an *ecdsa.PrivateKey with ES256, ES384 or ES512 depending
+// on its curve. Set Sign and Algorithm instead to sign with anything else,
+// such as a key held in a KMS.
+type JWTAuth struct {
+	Key       any
+	Issuer    string
+	Subject   string
+	Audience  []string
+	TTL       time.Duration
+	KeyID     string
+	Claims    map[string]any
+	Algorithm string
+	Sign      func(signingInput []byte) ([]byte, error)
+
+	mu     sync.Mutex
+	token  string
+	expiry time.Time
+
+	// now is used in tests to control the token time
+	now func() time.Time
+}
+
+// Apply injects a Bearer token when the request is sent, signing a new one
+// if needed, so reused and retried requests never carry an expired token
+func (p *JWTAuth) Apply(r *Request) *Request {
+	if r.err != nil {
+		return r
+	}
+
+	r.beforeSend = append(r.beforeSend, func(req *http.Request) error {
+		token, err := p.Token()
+		if err != nil {
+			return fmt.Errorf("jwt: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		return nil
+	})
+
+	return r
+}
+
+// Token returns a cached token or signs a new one
+func (p *JWTAuth) Token() (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now
+	if p.now != nil {
+		now = p.now
+	}
+	t := now()
+
+	if p.token != "" && t.Before(p.expiry) {
+		return p.token, nil
+	}
+
+	ttl := p.TTL
+	if ttl <= 0 {
+		ttl = defaultJWTTTL
+	}
+
+	alg, sign, err := p.signer()
+	if err != nil {
+		return "", err
+	}
+
+	header := map[string]string{"alg": alg, "typ": "JWT"}
+	if p.KeyID != "" {
+		header["kid"] = p.KeyID
+	}
+
+	claims := make(map[string]any, len(p.Claims)+6)
+	maps.Copy(claims, p.Claims)
+	if p.Issuer != "" {
+		claims["iss"] = p.Issuer
+	}
+	if p.Subject != "" {
+		claims["sub"] = p.Subject
+	}
+	switch len(p.Audience) {
+	case 0:
+	case 1:
+		claims["aud"] = p.Audience[0]
+	default:
+		claims["aud"] = p.Audience
+	}
+	claims["iat"] = t.Unix()
+	claims["exp"] = t.Add(ttl).Unix()
+
+	signingInput, err := jwtSegments(header, claims)
+	if err != nil {
+		return "", err
+	}
+
+	sig, err := sign([]byte(signingInput))
+	if err != nil {
+		return "", fmt.Errorf("sign token: %w", err)
+	}
+
+	p.token = signingInput + "." + base64.RawURLEncoding.EncodeToString(sig)
+	p.expiry = t.Add(ttl - min(ttl/10, oauth2ExpiryDelta))
+
+	return p.token, nil
+}
+
+// signer returns the JWT algorithm name and signing function for the provider
+func (p *JWTAuth) signer() (string, func([]byte) ([]byte, error), error) {
+	if p.Sign != nil {
+		if p.Algorithm == "" {
+			return "", nil, errors.New("algorithm is required with a custom sign function")
+		}
+		return p.Algorithm, p.Sign, nil
+	}
+
+	switch key := p.Key.(type) {
+	case []byte:
+		return "HS256", func(data []byte) ([]byte, error) {
+			mac := hmac.New(sha256.New, key)
+			mac.Write(data)
+			return mac.Sum(nil), nil
+		}, nil
+	case *rsa.PrivateKey:
+		return "RS256", func(data []byte) ([]byte, error) {
+			digest := sha256.Sum256(data)
+			return rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, digest[:])
+		}, nil
+	case *ecdsa.PrivateKey:
+		alg, newHash, err := ecdsaAlgorithm(key.Curve)
+		if err != nil {
+			return "", nil, err
+		}
+		return alg, func(data []byte) ([]byte, error) {
+			h := newHash()
+			h.Write(data)
+			r, s, err := ecdsa.Sign(rand.Reader, key, h.Sum(nil))
+			if err != nil {
+				return nil, err
+			}
+			size := (key.Curve.Params().BitSize + 7) / 8
+			sig := make([]byte, 2*size)
+			r.FillBytes(sig[:size])
+			s.FillBytes(sig[size:])
+			return sig, nil
+		}, nil
+	case nil:
+		return "", nil, errors.New("no signing key")
+	default:
+		return "", nil, fmt.Errorf("unsupported signing key type %T", key)
+	}
+}
+
+// ecdsaAlgorithm returns the JWT algorithm and hash matching an ECDSA curve
+func ecdsaAlgorithm(curve elliptic.Curve) (string, func() hash.Hash, error) {
+	switch curve {
+	case elliptic.P256():
+		return "ES256", sha256.New, nil
+	case elliptic.P384():
+		return "ES384", sha512.New384, nil
+	case elliptic.P521():
+		return "ES512", sha512.New, nil
+	default:
+		return "", nil, fmt.Errorf("unsupported ECDSA curve %s", curve.Params().Name)
+	}
+}
+
+// jwtSegments encodes the header and claims as the JWT signing input
+func jwtSegments(header map[string]string, claims map[string]any) (string, error) {
+	h, err := json.Marshal(header)
+	if err != nil {
+		return "", fmt.Errorf("encode header: %w", err)
+	}
+	c, err := json.Marshal(claims)
+	if err != nil {
+		return "", fmt.Errorf("encode claims: %w", err)
+	}
+	return base64.RawURLEncoding.EncodeToString(h) + "." + base64.RawURLEncoding.EncodeToString(c), nil
+}
@@ -0,0 +1,249 @@
+package rq
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestJWTAuth(t *testing.T) {
+	secret := []byte("secret")
+
+	var gotAuth string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotAuth = r.Header.Get("Authorization")
+	}))
+	defer srv.Close()
+
+	now := time.Unix(1700000000, 0)
+	provider := &JWTAuth{
+		Key:      secret,
+		Issuer:   "svc-a",
+		Subject:  "worker",
+		Audience: []string{"svc-b"},
+		TTL:      time.Minute,
+		KeyID:    "k1",
+		Claims:   map[string]any{"role": "admin"},
+		now:      func() time.Time { return now },
+	}
+
+	resp := Get(srv.URL).WithAuth(provider).Do()
+	if resp.Error() != nil {
+		t.Fatal(resp.Error())
+	}
+
+	token, ok := strings.CutPrefix(gotAuth, "Bearer ")
+	if !ok {
+		t.Fatalf("want Bearer token, got %q", gotAuth)
+	}
+
+	header, claims, sig, input := splitJWT(t, token)
+	if header["alg"] != "HS256" || header["typ"] != "JWT" || header["kid"] != "k1" {
+		t.Errorf("want HS256 JWT header with kid, got %v", header)
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(input))
+	if !hmac.Equal(sig, mac.Sum(nil)) {
+		t.Error("want valid HS256 signature")
+	}
+
+	want := map[string]any{
+		"iss":  "svc-a",
+		"sub":  "worker",
+		"aud":  "svc-b",
+		"role": "admin",
+		"iat":  float64(now.Unix()),
+		"exp":  float64(now.Add(time.Minute).Unix()),
+	}
+	for k, v := range want {
+		if claims[k] != v {
+			t.Errorf("want claim %s=%v, got %v", k, v, claims[k])
+		}
+	}
+
+	t.Run("cached until near expiry", func(t *testing.T) {
+		first, err := provider.Token()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if first != token {
+			t.Error("want cached token to be reused")
+		}
+
+		now = now.Add(55 * time.Second)
+		second, err := provider.Token()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if second == first {
+			t.Error("want new token near expiry")
+		}
+	})
+
+	t.Run("signed at send time", func(t *testing.T) {
+		req := Get(srv.URL).WithAuth(provider)
+
+		if resp := req.Do(); resp.Error() != nil {
+			t.Fatal(resp.Error())
+		}
+		first := gotAuth
+
+		provider.mu.Lock()
+		expiry := provider.expiry
+		provider.mu.Unlock()
+		now = expiry.Add(time.Second)
+
+		if resp := req.Do(); resp.Error() != nil {
+			t.Fatal(resp.Error())
+		}
+		if gotAuth == first {
+			t.Error("want reused request to send a fresh token after expiry")
+		}
+
+		_, claims, _, _ := splitJWT(t, strings.TrimPrefix(gotAuth, "Bearer "))
+		if claims["iat"] != float64(now.Unix()) {
+			t.Errorf("want token issued at %d, got %v", now.Unix(), claims["iat"])
+		}
+	})
+}
+
+func TestJWTAuthKeys(t *testing.T) {
+	rsaKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ecKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]struct {
+		provider *JWTAuth
+		wantAlg  string
+		verify   func(input string, sig []byte) bool
+	}{
+		"rsa": {
+			provider: &JWTAuth{Key: rsaKey},
+			wantAlg:  "RS256",
+			verify: func(input string, sig []byte) bool {
+				digest := sha256.Sum256([]byte(input))
+				return rsa.VerifyPKCS1v15(&rsaKey.PublicKey, crypto.SHA256, digest[:], sig) == nil
+			},
+		},
+		"ecdsa": {
+			provider: &JWTAuth{Key: ecKey},
+			wantAlg:  "ES256",
+			verify: func(input string, sig []byte) bool {
+				if len(sig) != 64 {
+					return false
+				}
+				digest := sha256.Sum256([]byte(input))
+				r := new(big.Int).SetBytes(sig[:32])
+				s := new(big.Int).SetBytes(sig[32:])
+				return ecdsa.Verify(&ecKey.PublicKey, digest[:], r, s)
+			},
+		},
+		"custom sign": {
+			provider: &JWTAuth{
+				Algorithm: "none-test",
+				Sign:      func([]byte) ([]byte, error) { return []byte("sig"), nil },
+			},
+			wantAlg: "none-test",
+			verify: func(input string, sig []byte) bool {
+				return string(sig) == "sig"
+			},
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			token, err := tc.provider.Token()
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			header, _, sig, input := splitJWT(t, token)
+			if header["alg"] != tc.wantAlg {
+				t.Errorf("want alg %s, got %s", tc.wantAlg, header["alg"])
+			}
+			if !tc.verify(input, sig) {
+				t.Error("want valid signature")
+			}
+		})
+	}
+
+	t.Run("missing key", func(t *testing.T) {
+		resp := Get("http://example.com").WithAuth(&JWTAuth{}).Do()
+		if resp.Error() == nil || !strings.Contains(resp.Error().Error(), "jwt:") {
+			t.Errorf("want jwt error, got %v", resp.Error())
+		}
+	})
+}
+
+func TestJWTAuthConcurrent(t *testing.T) {
+	provider := &JWTAuth{Key: []byte("secret")}
+
+	tokens := make([]string, 20)
+	var wg sync.WaitGroup
+	for i := range tokens {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			token, err := provider.Token()
+			if err != nil {
+				t.Error(err)
+			}
+			tokens[i] = token
+		}()
+	}
+	wg.Wait()
+
+	for _, token := range tokens {
+		if token != tokens[0] {
+			t.Fatal("want all goroutines to share one cached token")
+		}
+	}
+}
+
+// splitJWT decodes a compact JWT into its header, claims, signature and signing input
+func splitJWT(t *testing.T, token string) (map[string]string, map[string]any, []byte, string) {
+	t.Helper()
+
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		t.Fatalf("want 3 JWT segments, got %d", len(parts))
+	}
+
+	var header map[string]string
+	var claims map[string]any
+	for i, v := range []any{&header, &claims} {
+		data, err := base64.RawURLEncoding.DecodeString(parts[i])
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := json.Unmarshal(data, v); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return header, claims, sig, parts[0] + "." + parts[1]
+}
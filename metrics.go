@@ -0,0 +1,68 @@
+package rq
+
+import (
+	"net/http"
+	"time"
+)
+
+// MetricsCollector receives one observation per round trip. Implementations
+// adapt it to Prometheus, OpenTelemetry, statsd and the like, and must be
+// safe for concurrent use. status is 0 when err is not nil.
+type MetricsCollector interface {
+	ObserveRequest(method, host string, status int, dur time.Duration, err error)
+}
+
+// NopMetricsCollector is a MetricsCollector that discards all observations
+type NopMetricsCollector struct{}
+
+// ObserveRequest implements MetricsCollector
+func (NopMetricsCollector) ObserveRequest(string, string, int, time.Duration, error) {}
+
+// MetricsMiddleware reports every round trip to collector by wrapping the
+// client transport, so the duration covers the actual exchange up to the
+// response headers, and redirects and retries made by the transport are
+// observed individually. Apply it after options that need the underlying
+// *http.Transport, such as Proxy or TLSConfig. A nil collector is a no-op.
+func MetricsMiddleware(collector MetricsCollector) Middleware {
+	if collector == nil {
+		collector = NopMetricsCollector{}
+	}
+
+	return func(r *Request) *Request {
+		if r.err != nil {
+			return r
+		}
+
+		client := r.cloneClient()
+		client.Transport = &metricsTransport{base: client.Transport, collector: collector}
+		r.client = client
+
+		return r
+	}
+}
+
+// metricsTransport times round trips and reports them to a MetricsCollector
+type metricsTransport struct {
+	base      http.RoundTripper
+	collector MetricsCollector
+}
+
+// RoundTrip implements the RoundTripper interface
+func (t *metricsTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	start := time.Now()
+	resp, err := base.RoundTrip(req)
+	dur := time.Since(start)
+
+	status := 0
+	if err == nil {
+		status = resp.StatusCode
+	}
+	t.collector.ObserveRequest(req.Method, req.URL.Host, status, dur, err)
+
+	return resp, err
+}
@@ -0,0 +1,76 @@
+package rq
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type observation struct {
+	method string
+	host   string
+	status int
+	dur    time.Duration
+	err    error
+}
+
+type recordingCollector struct {
+	mu           sync.Mutex
+	observations []observation
+}
+
+func (c *recordingCollector) ObserveRequest(method, host string, status int, dur time.Duration, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.observations = append(c.observations, observation{method, host, status, dur, err})
+}
+
+func TestMetricsMiddleware(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(10 * time.Millisecond)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer srv.Close()
+
+	collector := &recordingCollector{}
+
+	resp := Post(srv.URL).Use(MetricsMiddleware(collector)).Do()
+	if resp.Error() != nil {
+		t.Fatal(resp.Error())
+	}
+
+	Get("http://127.0.0.1:1").Use(MetricsMiddleware(collector)).Do()
+
+	if len(collector.observations) != 2 {
+		t.Fatalf("want 2 observations, got %d", len(collector.observations))
+	}
+
+	ok := collector.observations[0]
+	if ok.method != http.MethodPost || ok.status != http.StatusNotFound || ok.err != nil {
+		t.Errorf("want POST 404 without error, got %+v", ok)
+	}
+	if ok.host != strings.TrimPrefix(srv.URL, "http://") {
+		t.Errorf("want host %q, got %q", strings.TrimPrefix(srv.URL, "http://"), ok.host)
+	}
+	if ok.dur < 10*time.Millisecond {
+		t.Errorf("want duration of at least 10ms, got %v", ok.dur)
+	}
+
+	failed := collector.observations[1]
+	if failed.err == nil || failed.status != 0 {
+		t.Errorf("want error with status 0, got %+v", failed)
+	}
+}
+
+func TestMetricsMiddlewareNilCollector(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	resp := Get(srv.URL).Use(MetricsMiddleware(nil)).Do()
+	if resp.Error() != nil {
+		t.Errorf("want no error, got %v", resp.Error())
+	}
+}
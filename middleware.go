@@ -1,8 +1,11 @@
 package rq
 
 import (
+	"context"
+	"fmt"
 	"log"
 	"net/http"
+	"strconv"
 	"time"
 )
 
@@ -49,6 +52,13 @@ func UserAgentMiddleware(userAgent string) Middleware {
 	}
 }
 
+// RetryMiddleware makes requests retry according to config, see Request.Retry
+func RetryMiddleware(config *RetryConfig) Middleware {
+	return func(r *Request) *Request {
+		return r.Retry(config)
+	}
+}
+
 // TimeoutMiddleware sets a timeout for the request
 func TimeoutMiddleware(timeout time.Duration) Middleware {
 	return func(r *Request) *Request {
@@ -63,8 +73,131 @@ func HeadersMiddleware(headers map[string]string) Middleware {
 	}
 }
 
+// DeadlinePropagationMiddleware propagates a caller's deadline carried in
+// the named header of incoming, such as the headers of the server request
+// being handled, with grpc-timeout or X-Request-Timeout. Values may use the
+// grpc-timeout format ("100m", "5S") or a Go duration ("1.5s"). The request
+// timeout is only ever shortened, and missing or malformed values are
+// ignored. The header is sent downstream with the budget remaining at each
+// send, in the format it arrived in; a request whose budget has run out is
+// not sent.
+func DeadlinePropagationMiddleware(headerName string, incoming http.Header) Middleware {
+	return func(r *Request) *Request {
+		if r.err != nil {
+			return r
+		}
+
+		value := incoming.Get(headerName)
+		if value == "" {
+			return r
+		}
+
+		timeout, grpc, err := parseTimeoutHeader(value)
+		if err != nil || timeout <= 0 {
+			return r
+		}
+		deadline := time.Now().Add(timeout)
+
+		if r.timeout <= 0 || r.timeout > timeout {
+			r.Timeout(timeout)
+		}
+
+		r.beforeSend = append(r.beforeSend, func(req *http.Request) error {
+			remaining := time.Until(deadline)
+			if d, ok := req.Context().Deadline(); ok {
+				remaining = min(remaining, time.Until(d))
+			}
+			if remaining <= 0 {
+				return fmt.Errorf("propagated deadline: %w", context.DeadlineExceeded)
+			}
+			req.Header.Set(headerName, formatTimeoutHeader(remaining, grpc))
+			return nil
+		})
+
+		return r
+	}
+}
+
+// grpcTimeoutUnits maps grpc-timeout unit suffixes to durations
+var grpcTimeoutUnits = map[byte]time.Duration{
+	'H': time.Hour,
+	'M': time.Minute,
+	'S': time.Second,
+	'm': time.Millisecond,
+	'u': time.Microsecond,
+	'n': time.Nanosecond,
+}
+
+// parseTimeoutHeader parses a grpc-timeout value or a Go duration string,
+// reporting which of the two it was
+func parseTimeoutHeader(value string) (d time.Duration, grpc bool, err error) {
+	if len(value) >= 2 {
+		if unit, ok := grpcTimeoutUnits[value[len(value)-1]]; ok {
+			if n, err := strconv.ParseInt(value[:len(value)-1], 10, 64); err == nil {
+				return time.Duration(n) * unit, true, nil
+			}
+		}
+	}
+
+	d, err = time.ParseDuration(value)
+	if err != nil {
+		return 0, false, fmt.Errorf("invalid timeout %q: %w", value, err)
+	}
+
+	return d, false, nil
+}
+
+// formatTimeoutHeader formats d as a grpc-timeout value in milliseconds or
+// as a Go duration, rounded up so a small budget is not sent as zero
+func formatTimeoutHeader(d time.Duration, grpc bool) string {
+	ms := (d + time.Millisecond - 1) / time.Millisecond
+	if grpc {
+		return strconv.FormatInt(int64(ms), 10) + "m"
+	}
+	return (ms * time.Millisecond).String()
+}
+
+// RequestIDHeader is the header set by RequestIDMiddleware
+const RequestIDHeader = "X-Request-ID"
+
+// RequestIDMiddleware sets an X-Request-ID header on the request, unless it
+// already has one, so it can be correlated across logs. The ID is generated
+// once when the middleware is applied, with generator or as a random UUID
+// when generator is nil, so every retry of the request carries the same ID.
+// It is available from Response.RequestID.
+func RequestIDMiddleware(generator func() string) Middleware {
+	if generator == nil {
+		generator = func() string {
+			id, _ := newUUID()
+			return id
+		}
+	}
+
+	return func(r *Request) *Request {
+		if r.err != nil {
+			return r
+		}
+
+		id := generator()
+		r.requestID = id
+		r.beforeSend = append(r.beforeSend, func(req *http.Request) error {
+			if req.Header.Get(RequestIDHeader) == "" {
+				req.Header.Set(RequestIDHeader, id)
+			}
+			return nil
+		})
+		return r
+	}
+}
+
 // DumpMiddleware enables HTTP request/response dumping using DumpTransport
 func DumpMiddleware(logger *log.Logger) Middleware {
+	return DumpMiddlewareWithOptions(logger, DumpOptions{})
+}
+
+// DumpMiddlewareWithOptions enables HTTP request/response dumping with the
+// redaction settings of opts, see DumpTransportWithOptions
+func DumpMiddlewareWithOptions(logger *log.Logger, opts DumpOptions) Middleware {
 	return func(r *Request) *Request {
 		if r.err != nil {
 			return r
@@ -77,7 +210,7 @@ func DumpMiddleware(logger *log.Logger) Middleware {
 
 		// http.Client has only 4 fields. We copy all of them
 		dumpClient := &http.Client{
-			Transport:     DumpTransport(client.Transport, logger),
+			Transport:     DumpTransportWithOptions(client.Transport, logger, opts),
 			CheckRedirect: client.CheckRedirect,
 			Jar:           client.Jar,
 			Timeout:       client.Timeout,
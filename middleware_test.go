@@ -2,12 +2,17 @@ package rq
 
 import (
 	"bytes"
+	"context"
+	"errors"
 	"fmt"
 	"log"
 	"net/http"
 	"net/http/cookiejar"
 	"net/http/httptest"
+	"slices"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -76,6 +81,126 @@ func TestTimeoutMiddleware(t *testing.T) {
 	}
 }
 
+func TestDeadlinePropagationMiddleware(t *testing.T) {
+	tests := map[string]struct {
+		header      string
+		timeout     time.Duration
+		wantTimeout time.Duration
+	}{
+		"grpc milliseconds": {
+			header:      "100m",
+			wantTimeout: 100 * time.Millisecond,
+		},
+		"grpc seconds": {
+			header:      "5S",
+			wantTimeout: 5 * time.Second,
+		},
+		"go duration": {
+			header:      "1.5s",
+			wantTimeout: 1500 * time.Millisecond,
+		},
+		"keeps shorter timeout": {
+			header:      "5S",
+			timeout:     time.Second,
+			wantTimeout: time.Second,
+		},
+		"shortens longer timeout": {
+			header:      "100m",
+			timeout:     time.Second,
+			wantTimeout: 100 * time.Millisecond,
+		},
+		"missing header": {
+			wantTimeout: 0,
+		},
+		"malformed header": {
+			header:      "soon",
+			wantTimeout: 0,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			incoming := http.Header{}
+			if tt.header != "" {
+				incoming.Set("Grpc-Timeout", tt.header)
+			}
+
+			r := New()
+			if tt.timeout > 0 {
+				r.Timeout(tt.timeout)
+			}
+
+			r = r.Use(DeadlinePropagationMiddleware("grpc-timeout", incoming))
+			if r.timeout != tt.wantTimeout {
+				t.Errorf("want timeout %v, got %v", tt.wantTimeout, r.timeout)
+			}
+		})
+	}
+
+	t.Run("deadline is enforced", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			time.Sleep(100 * time.Millisecond)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer srv.Close()
+
+		incoming := http.Header{"X-Request-Timeout": {"20ms"}}
+		resp := Get(srv.URL).
+			Use(DeadlinePropagationMiddleware("X-Request-Timeout", incoming)).
+			Do()
+		if resp.Error() == nil {
+			t.Error("want timeout error")
+		}
+	})
+
+	t.Run("forwards remaining budget", func(t *testing.T) {
+		var got string
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			got = r.Header.Get("Grpc-Timeout")
+		}))
+		defer srv.Close()
+
+		incoming := http.Header{"Grpc-Timeout": {"5S"}}
+		req := Get(srv.URL).
+			Header("Grpc-Timeout", "5S").
+			Use(DeadlinePropagationMiddleware("grpc-timeout", incoming))
+		time.Sleep(20 * time.Millisecond)
+
+		resp := req.Do()
+		if resp.Error() != nil {
+			t.Fatal(resp.Error())
+		}
+
+		remaining, grpc, err := parseTimeoutHeader(got)
+		if err != nil || !grpc {
+			t.Fatalf("want grpc-timeout value, got %q", got)
+		}
+		if remaining <= 0 || remaining > 4990*time.Millisecond {
+			t.Errorf("want remaining budget of at most 4.99s, got %v", remaining)
+		}
+	})
+
+	t.Run("exhausted budget is not sent", func(t *testing.T) {
+		var hits int32
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&hits, 1)
+		}))
+		defer srv.Close()
+
+		incoming := http.Header{"X-Request-Timeout": {"10ms"}}
+		req := Get(srv.URL).Use(DeadlinePropagationMiddleware("X-Request-Timeout", incoming))
+		time.Sleep(20 * time.Millisecond)
+
+		resp := req.Do()
+		if !errors.Is(resp.Error(), context.DeadlineExceeded) {
+			t.Errorf("want context.DeadlineExceeded, got %v", resp.Error())
+		}
+		if got := atomic.LoadInt32(&hits); got != 0 {
+			t.Errorf("want no request sent, got %d", got)
+		}
+	})
+}
+
 func TestHeadersMiddleware(t *testing.T) {
 	headers := map[string]string{
 		"X-App-Name":    "TestApp",
@@ -254,3 +379,101 @@ func TestUseMethodStarting(t *testing.T) {
 		t.Fatal(resp.Error())
 	}
 }
+
+func TestRequestIDMiddleware(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Header.Get(RequestIDHeader)))
+	}))
+	defer srv.Close()
+
+	tests := map[string]struct {
+		generator func() string
+		header    string
+		want      string
+	}{
+		"generated": {
+			generator: func() string { return "req-1" },
+			want:      "req-1",
+		},
+		"caller header kept": {
+			generator: func() string { return "req-1" },
+			header:    "caller-id",
+			want:      "caller-id",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var hooked string
+			req := Use(RequestIDMiddleware(tc.generator)).URL(srv.URL).
+				OnResponse(func(resp *Response) {
+					hooked = resp.RequestID()
+				})
+			if tc.header != "" {
+				req = req.Header(RequestIDHeader, tc.header)
+			}
+
+			resp := req.Do()
+			if resp.Error() != nil {
+				t.Fatal(resp.Error())
+			}
+
+			if body, _ := resp.String(); body != tc.want {
+				t.Errorf("want header %q sent, got %q", tc.want, body)
+			}
+			if resp.RequestID() != tc.want {
+				t.Errorf("want RequestID %q, got %q", tc.want, resp.RequestID())
+			}
+			if hooked != tc.want {
+				t.Errorf("want RequestID %q in hook, got %q", tc.want, hooked)
+			}
+		})
+	}
+
+	t.Run("default generator", func(t *testing.T) {
+		first := Use(RequestIDMiddleware(nil)).URL(srv.URL).Do()
+		second := Use(RequestIDMiddleware(nil)).URL(srv.URL).Do()
+
+		if len(first.RequestID()) != 36 {
+			t.Errorf("want UUID request ID, got %q", first.RequestID())
+		}
+		if first.RequestID() == second.RequestID() {
+			t.Errorf("want unique request IDs, got %q twice", first.RequestID())
+		}
+	})
+
+	t.Run("same ID across retries", func(t *testing.T) {
+		var mu sync.Mutex
+		var sent []string
+		flaky := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			mu.Lock()
+			defer mu.Unlock()
+			sent = append(sent, r.Header.Get(RequestIDHeader))
+			if len(sent) < 3 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+			}
+		}))
+		defer flaky.Close()
+
+		var generated int
+		generator := func() string {
+			generated++
+			return fmt.Sprintf("req-%d", generated)
+		}
+
+		resp := Get(flaky.URL).
+			Use(RequestIDMiddleware(generator)).
+			Retry(&RetryConfig{MaxAttempts: 3, Delay: time.Millisecond, RetryIf: defaultRetryIf}).
+			Do()
+		if resp.Error() != nil {
+			t.Fatal(resp.Error())
+		}
+
+		if want := []string{"req-1", "req-1", "req-1"}; !slices.Equal(sent, want) {
+			t.Errorf("want IDs %v, got %v", want, sent)
+		}
+		if resp.RequestID() != "req-1" {
+			t.Errorf("want RequestID req-1, got %q", resp.RequestID())
+		}
+	})
+}
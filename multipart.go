@@ -0,0 +1,82 @@
+package rq
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"strings"
+)
+
+// Part is a single part of a multipart response
+type Part struct {
+	Header textproto.MIMEHeader
+	Body   []byte
+
+	// Parts holds the sub-parts when the part is itself multipart
+	Parts []Part
+}
+
+// Multipart splits a multipart response, such as the multipart/mixed reply
+// of a batch API, into its parts using the boundary from the Content-Type.
+// Parts that are multipart themselves are split recursively into Parts.
+func (r *Response) Multipart() ([]Part, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	if r.Response == nil {
+		return nil, errors.New("multipart: no response")
+	}
+	if r.stream {
+		return nil, errors.New("multipart: streamed response body is not available")
+	}
+
+	return parseMultipart(r.Header.Get("Content-Type"), r.body)
+}
+
+// parseMultipart parses body as the multipart content described by contentType
+func parseMultipart(contentType string, body []byte) ([]Part, error) {
+	mediaType, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return nil, fmt.Errorf("multipart: invalid Content-Type %q: %w", contentType, err)
+	}
+	if !strings.HasPrefix(mediaType, "multipart/") {
+		return nil, fmt.Errorf("multipart: Content-Type %s is not multipart", mediaType)
+	}
+
+	boundary := params["boundary"]
+	if boundary == "" {
+		return nil, errors.New("multipart: missing boundary in Content-Type")
+	}
+
+	reader := multipart.NewReader(bytes.NewReader(body), boundary)
+
+	var parts []Part
+	for {
+		p, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			return parts, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("multipart: read part %d: %w", len(parts), err)
+		}
+
+		data, err := io.ReadAll(p)
+		if err != nil {
+			return nil, fmt.Errorf("multipart: read part %d: %w", len(parts), err)
+		}
+
+		part := Part{Header: p.Header, Body: data}
+		if partType := p.Header.Get("Content-Type"); strings.HasPrefix(strings.ToLower(partType), "multipart/") {
+			part.Parts, err = parseMultipart(partType, data)
+			if err != nil {
+				return nil, fmt.Errorf("part %d: %w", len(parts), err)
+			}
+		}
+
+		parts = append(parts, part)
+	}
+}
@@ -0,0 +1,83 @@
+package rq
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestMultipart(t *testing.T) {
+	batch := strings.Join([]string{
+		"--outer",
+		"Content-Type: application/http",
+		"Content-ID: <1>",
+		"",
+		"HTTP/1.1 200 OK",
+		"--outer",
+		"Content-Type: multipart/alternative; boundary=inner",
+		"",
+		"--inner",
+		"Content-Type: text/plain",
+		"",
+		"plain",
+		"--inner",
+		"Content-Type: text/html",
+		"",
+		"<p>html</p>",
+		"--inner--",
+		"--outer--",
+		"",
+	}, "\r\n")
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/batch":
+			w.Header().Set("Content-Type", "multipart/mixed; boundary=outer")
+		case "/no-boundary":
+			w.Header().Set("Content-Type", "multipart/mixed")
+		default:
+			w.Header().Set("Content-Type", "application/json")
+		}
+		w.Write([]byte(batch))
+	}))
+	defer srv.Close()
+
+	t.Run("batch", func(t *testing.T) {
+		parts, err := Get(srv.URL + "/batch").Do().Multipart()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(parts) != 2 {
+			t.Fatalf("want 2 parts, got %d", len(parts))
+		}
+
+		if got := parts[0].Header.Get("Content-ID"); got != "<1>" {
+			t.Errorf("want Content-ID <1>, got %q", got)
+		}
+		if got := string(parts[0].Body); got != "HTTP/1.1 200 OK" {
+			t.Errorf("want first body %q, got %q", "HTTP/1.1 200 OK", got)
+		}
+
+		nested := parts[1].Parts
+		if len(nested) != 2 {
+			t.Fatalf("want 2 nested parts, got %d", len(nested))
+		}
+		if got := string(nested[1].Body); got != "<p>html</p>" {
+			t.Errorf("want nested body <p>html</p>, got %q", got)
+		}
+	})
+
+	t.Run("missing boundary", func(t *testing.T) {
+		_, err := Get(srv.URL + "/no-boundary").Do().Multipart()
+		if err == nil || !strings.Contains(err.Error(), "boundary") {
+			t.Errorf("want missing boundary error, got %v", err)
+		}
+	})
+
+	t.Run("not multipart", func(t *testing.T) {
+		if _, err := Get(srv.URL + "/json").Do().Multipart(); err == nil {
+			t.Error("want error for non-multipart response, got nil")
+		}
+	})
+}
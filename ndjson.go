@@ -0,0 +1,145 @@
+package rq
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+)
+
+// BodyNDJSON creates a new request with a newline-delimited JSON body
+func BodyNDJSON(items any) *Request {
+	return New().BodyNDJSON(items)
+}
+
+// BodyNDJSON sets the request body to newline-delimited JSON, one line per
+// element of items, which must be a slice, an array or a channel. A slice
+// is encoded up front; a channel is streamed as items arrive until it is
+// closed, so the request cannot be replayed or retried. An empty slice
+// sends an empty body.
+func (r *Request) BodyNDJSON(items any) *Request {
+	if r.err != nil {
+		return r
+	}
+
+	v := reflect.ValueOf(items)
+	switch v.Kind() {
+	case reflect.Slice, reflect.Array:
+		var buf bytes.Buffer
+		enc := json.NewEncoder(&buf)
+		for i := 0; i < v.Len(); i++ {
+			if err := enc.Encode(v.Index(i).Interface()); err != nil {
+				r.err = fmt.Errorf("failed to marshal NDJSON item %d: %w", i, err)
+				return r
+			}
+		}
+		r.setBodyBytes(buf.Bytes())
+	case reflect.Chan:
+		if v.Type().ChanDir()&reflect.RecvDir == 0 {
+			r.err = fmt.Errorf("BodyNDJSON: cannot receive from %s", v.Type())
+			return r
+		}
+		r.Body(&ndjsonChanReader{ch: v})
+	default:
+		r.err = fmt.Errorf("BodyNDJSON: expected slice, array or channel, got %T", items)
+		return r
+	}
+
+	r.header().Set("Content-Type", "application/x-ndjson")
+	return r
+}
+
+// ndjsonChanReader encodes channel items as NDJSON while the body is read.
+// Encoding starts on the first Read and ends when the channel is closed;
+// after the body is closed, the next received item stops it.
+type ndjsonChanReader struct {
+	ch reflect.Value
+
+	mu     sync.Mutex
+	pr     *io.PipeReader
+	closed bool
+}
+
+func (c *ndjsonChanReader) Read(p []byte) (int, error) {
+	c.mu.Lock()
+	if c.pr == nil {
+		if c.closed {
+			c.mu.Unlock()
+			return 0, io.ErrClosedPipe
+		}
+		pr, pw := io.Pipe()
+		c.pr = pr
+		go encodeNDJSON(c.ch, pw)
+	}
+	pr := c.pr
+	c.mu.Unlock()
+
+	return pr.Read(p)
+}
+
+func (c *ndjsonChanReader) Close() error {
+	c.mu.Lock()
+	c.closed = true
+	pr := c.pr
+	c.mu.Unlock()
+
+	if pr != nil {
+		return pr.Close()
+	}
+	return nil
+}
+
+// encodeNDJSON writes the items received from ch to pw until ch is closed
+func encodeNDJSON(ch reflect.Value, pw *io.PipeWriter) {
+	enc := json.NewEncoder(pw)
+	for i := 0; ; i++ {
+		item, ok := ch.Recv()
+		if !ok {
+			_ = pw.Close()
+			return
+		}
+		if err := enc.Encode(item.Interface()); err != nil {
+			_ = pw.CloseWithError(fmt.Errorf("marshal NDJSON item %d: %w", i, err))
+			return
+		}
+	}
+}
+
+// EachLine calls fn with every non-blank line of the response body, without
+// the line ending. Iteration stops at the first error returned by fn, which
+// is returned as is.
+func (r *Response) EachLine(fn func(line []byte) error) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	for line := range bytes.Lines(r.body) {
+		line = bytes.TrimRight(line, "\r\n")
+		if len(bytes.TrimSpace(line)) == 0 {
+			continue
+		}
+		if err := fn(line); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// NDJSON iterates a newline-delimited JSON body, calling fn once per line
+// with a decode function that unmarshals the line into v. Blank lines are
+// skipped and iteration stops at the first error returned by fn.
+func (r *Response) NDJSON(fn func(decode func(v any) error) error) error {
+	n := 0
+	return r.EachLine(func(line []byte) error {
+		n++
+		return fn(func(v any) error {
+			if err := json.Unmarshal(line, v); err != nil {
+				return fmt.Errorf("decode NDJSON line %d: %w", n, err)
+			}
+			return nil
+		})
+	})
+}
@@ -0,0 +1,140 @@
+package rq
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestBodyNDJSON(t *testing.T) {
+	type event struct {
+		ID int `json:"id"`
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Content-Type"); got != "application/x-ndjson" {
+			t.Errorf("want Content-Type application/x-ndjson, got %q", got)
+		}
+		body, _ := io.ReadAll(r.Body)
+		w.Write(body)
+	}))
+	defer srv.Close()
+
+	ch := make(chan event)
+	go func() {
+		for i := 1; i <= 3; i++ {
+			ch <- event{ID: i}
+		}
+		close(ch)
+	}()
+
+	tests := map[string]struct {
+		items any
+		want  string
+	}{
+		"slice": {
+			items: []event{{ID: 1}, {ID: 2}},
+			want:  "{\"id\":1}\n{\"id\":2}\n",
+		},
+		"empty slice": {
+			items: []event{},
+			want:  "",
+		},
+		"channel": {
+			items: ch,
+			want:  "{\"id\":1}\n{\"id\":2}\n{\"id\":3}\n",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			got, err := Post(srv.URL).BodyNDJSON(tt.items).Do().String()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("want body %q, got %q", tt.want, got)
+			}
+		})
+	}
+
+	t.Run("invalid input", func(t *testing.T) {
+		if err := BodyNDJSON(map[string]int{}).err; err == nil {
+			t.Error("want error for map input, got nil")
+		}
+	})
+
+	t.Run("unencodable channel item", func(t *testing.T) {
+		bad := make(chan any, 1)
+		bad <- func() {}
+		close(bad)
+
+		if err := Post(srv.URL).BodyNDJSON(bad).Do().Error(); err == nil {
+			t.Error("want encoding error, got nil")
+		}
+	})
+}
+
+func TestResponseNDJSON(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{\"id\":1}\r\n\n  \n{\"id\":2}\n{\"id\":3}"))
+	}))
+	defer srv.Close()
+
+	resp := Get(srv.URL).Do()
+
+	t.Run("EachLine", func(t *testing.T) {
+		var lines []string
+		err := resp.EachLine(func(line []byte) error {
+			lines = append(lines, string(line))
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if want := `{"id":1},{"id":2},{"id":3}`; strings.Join(lines, ",") != want {
+			t.Errorf("want lines %s, got %s", want, strings.Join(lines, ","))
+		}
+	})
+
+	t.Run("NDJSON", func(t *testing.T) {
+		var ids []int
+		err := resp.NDJSON(func(decode func(v any) error) error {
+			var item struct {
+				ID int `json:"id"`
+			}
+			if err := decode(&item); err != nil {
+				return err
+			}
+			ids = append(ids, item.ID)
+			return nil
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(ids) != 3 || ids[2] != 3 {
+			t.Errorf("want ids [1 2 3], got %v", ids)
+		}
+	})
+
+	t.Run("stops on error", func(t *testing.T) {
+		stop := errors.New("stop")
+		calls := 0
+		err := resp.EachLine(func([]byte) error {
+			calls++
+			return stop
+		})
+		if !errors.Is(err, stop) || calls != 1 {
+			t.Errorf("want stop after 1 call, got %v after %d calls", err, calls)
+		}
+	})
+
+	t.Run("request error", func(t *testing.T) {
+		if err := New().Do().EachLine(func([]byte) error { return nil }); err == nil {
+			t.Error("want request error, got nil")
+		}
+	})
+}
@@ -0,0 +1,127 @@
+package rq
+
+import (
+	"net"
+	"net/url"
+	"strings"
+)
+
+// noProxyRule is a single NO_PROXY entry
+type noProxyRule struct {
+	domain string
+	ip     net.IP
+	cidr   *net.IPNet
+	port   string
+	// exact is false for entries with a leading dot, which only match subdomains
+	exact bool
+}
+
+type noProxyRules struct {
+	all   bool
+	rules []noProxyRule
+}
+
+// parseNoProxy parses NO_PROXY entries following the rules of
+// golang.org/x/net/http/httpproxy: "*" matches everything, CIDR ranges and
+// IP addresses match IP hosts, "example.com" matches the domain and its
+// subdomains, ".example.com" and "*.example.com" only subdomains. Any entry
+// may carry a port.
+func parseNoProxy(entries []string) noProxyRules {
+	var r noProxyRules
+
+	for _, entry := range entries {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry == "" {
+			continue
+		}
+		if entry == "*" {
+			r.all = true
+			continue
+		}
+
+		if _, cidr, err := net.ParseCIDR(entry); err == nil {
+			r.rules = append(r.rules, noProxyRule{cidr: cidr})
+			continue
+		}
+
+		host, port, err := net.SplitHostPort(entry)
+		if err != nil {
+			host, port = entry, ""
+		}
+
+		if ip := net.ParseIP(strings.Trim(host, "[]")); ip != nil {
+			r.rules = append(r.rules, noProxyRule{ip: ip, port: port})
+			continue
+		}
+
+		rule := noProxyRule{domain: host, port: port, exact: true}
+		if strings.HasPrefix(rule.domain, "*.") {
+			rule.domain = rule.domain[1:]
+		}
+		if strings.HasPrefix(rule.domain, ".") {
+			rule.domain = rule.domain[1:]
+			rule.exact = false
+		}
+		r.rules = append(r.rules, rule)
+	}
+
+	return r
+}
+
+// match reports whether hostport should be dialed directly.
+// Like NO_PROXY, localhost and loopback addresses always match.
+func (r noProxyRules) match(hostport string) bool {
+	if r.all {
+		return true
+	}
+
+	host, port, err := net.SplitHostPort(hostport)
+	if err != nil {
+		host, port = hostport, ""
+	}
+	host = strings.ToLower(host)
+
+	if host == "localhost" {
+		return true
+	}
+
+	ip := net.ParseIP(host)
+	if ip != nil && ip.IsLoopback() {
+		return true
+	}
+
+	for _, rule := range r.rules {
+		if rule.port != "" && rule.port != port {
+			continue
+		}
+
+		switch {
+		case rule.cidr != nil:
+			if ip != nil && rule.cidr.Contains(ip) {
+				return true
+			}
+		case rule.ip != nil:
+			if ip != nil && rule.ip.Equal(ip) {
+				return true
+			}
+		case ip == nil:
+			if strings.HasSuffix(host, "."+rule.domain) || (rule.exact && host == rule.domain) {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+// canonicalAddr returns the host:port of u, adding the default port for its scheme
+func canonicalAddr(u *url.URL) string {
+	port := u.Port()
+	if port == "" {
+		port = "80"
+		if u.Scheme == "https" {
+			port = "443"
+		}
+	}
+	return net.JoinHostPort(u.Hostname(), port)
+}
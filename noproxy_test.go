@@ -0,0 +1,95 @@
+package rq
+
+import (
+	"net/http"
+	"testing"
+)
+
+func TestNoProxyMatch(t *testing.T) {
+	rules := parseNoProxy([]string{
+		"internal.example.com",
+		".corp.example.com",
+		"*.svc.local",
+		"10.0.0.0/8",
+		"192.168.1.10",
+		"api.example.com:8443",
+	})
+
+	tests := map[string]bool{
+		"internal.example.com:443":    true,
+		"a.internal.example.com:80":   true,
+		"corp.example.com:443":        false,
+		"x.corp.example.com:443":      true,
+		"db.svc.local:5432":           true,
+		"10.1.2.3:80":                 true,
+		"11.1.2.3:80":                 false,
+		"192.168.1.10:443":            true,
+		"api.example.com:8443":        true,
+		"api.example.com:443":         false,
+		"localhost:8080":              true,
+		"127.0.0.1:8080":              true,
+		"[::1]:8080":                  true,
+		"example.com:443":             false,
+		"notinternal.example.com:443": false,
+		"INTERNAL.EXAMPLE.COM:443":    true,
+	}
+
+	for hostport, want := range tests {
+		t.Run(hostport, func(t *testing.T) {
+			if got := rules.match(hostport); got != want {
+				t.Errorf("want match %v, got %v", want, got)
+			}
+		})
+	}
+
+	if !parseNoProxy([]string{"*"}).match("anything.example.com:443") {
+		t.Error("want * to match every host")
+	}
+}
+
+func TestProxyConfigNoProxy(t *testing.T) {
+	config := &ProxyConfig{
+		Type:    ProxyTypeHTTP,
+		Host:    "proxy.example.com",
+		Port:    "8080",
+		NoProxy: []string{"internal.example.com"},
+	}
+
+	transport, err := config.CreateTransport(nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := map[string]bool{
+		"https://internal.example.com/": false,
+		"http://api.example.com/":       true,
+	}
+
+	for rawURL, wantProxy := range tests {
+		t.Run(rawURL, func(t *testing.T) {
+			req, _ := http.NewRequest(http.MethodGet, rawURL, nil)
+			proxyURL, err := transport.Proxy(req)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := proxyURL != nil; got != wantProxy {
+				t.Errorf("want proxied %v, got %v", wantProxy, got)
+			}
+		})
+	}
+}
+
+func TestProxyFromEnvironment(t *testing.T) {
+	r := Get("http://example.com").ProxyFromEnvironment()
+	if r.err != nil {
+		t.Fatal(r.err)
+	}
+
+	transport := getTransport(r.client)
+	if transport == nil || transport.Proxy == nil {
+		t.Fatal("want transport with an environment proxy func")
+	}
+	if transport == http.DefaultTransport {
+		t.Error("want default transport not to be modified")
+	}
+}
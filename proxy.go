@@ -1,6 +1,7 @@
 package rq
 
 import (
+	"context"
 	"fmt"
 	"net"
 	"net/http"
@@ -10,6 +11,9 @@ import (
 	"golang.org/x/net/proxy"
 )
 
+// defaultSOCKS5DialTimeout bounds connecting to a SOCKS5 proxy when DialTimeout is unset
+const defaultSOCKS5DialTimeout = 30 * time.Second
+
 type ProxyType string
 
 const (
@@ -24,6 +28,15 @@ type ProxyConfig struct {
 	Port     string
 	Username string
 	Password string
+
+	// DialTimeout bounds connecting to a SOCKS5 proxy. Defaults to 30s.
+	DialTimeout time.Duration
+
+	// NoProxy lists hosts that are dialed directly, using the NO_PROXY
+	// syntax: domain suffixes, IP addresses, CIDR ranges and optional ports.
+	// As with NO_PROXY, localhost and loopback addresses always bypass the
+	// proxy when NoProxy is set.
+	NoProxy []string
 }
 
 // ProxyFromURL creates a ProxyConfig from a URL string
@@ -95,12 +108,38 @@ func (p *ProxyConfig) CreateTransport(baseTransport *http.Transport) (*http.Tran
 	switch p.Type {
 	case ProxyTypeHTTP, ProxyTypeHTTPS:
 		baseTransport.Proxy = http.ProxyURL(p.URL())
+		if bypass := p.bypass(); bypass != nil {
+			proxyURL := p.URL()
+			baseTransport.Proxy = func(req *http.Request) (*url.URL, error) {
+				if bypass(canonicalAddr(req.URL)) {
+					return nil, nil
+				}
+				return proxyURL, nil
+			}
+		}
 	case ProxyTypeSOCKS5:
 		dialer, err := p.createSOCK5Dialer()
 		if err != nil {
 			return nil, fmt.Errorf("create SOCKS5 dialer: %w", err)
 		}
-		baseTransport.DialContext = dialer.DialContext
+		dial := dialer.DialContext
+		if bypass := p.bypass(); bypass != nil {
+			direct := baseTransport.DialContext
+			if direct == nil {
+				direct = (&net.Dialer{}).DialContext
+			}
+			dial = func(ctx context.Context, network, addr string) (net.Conn, error) {
+				if bypass(addr) {
+					return direct(ctx, network, addr)
+				}
+				return dialer.DialContext(ctx, network, addr)
+			}
+		}
+		baseTransport.DialContext = dial
+		// TLS must run over the tunnel to the origin, so custom TLS dialers
+		// that would bypass the proxy are dropped
+		baseTransport.DialTLSContext = nil
+		baseTransport.DialTLS = nil
 	default:
 		return nil, fmt.Errorf("unsupported proxy type: %s", p.Type)
 	}
@@ -108,6 +147,15 @@ func (p *ProxyConfig) CreateTransport(baseTransport *http.Transport) (*http.Tran
 	return baseTransport, nil
 }
 
+// bypass returns a matcher for hosts listed in NoProxy, or nil if there are none
+func (p *ProxyConfig) bypass() func(hostport string) bool {
+	if len(p.NoProxy) == 0 {
+		return nil
+	}
+
+	return parseNoProxy(p.NoProxy).match
+}
+
 func (p *ProxyConfig) createSOCK5Dialer() (proxy.ContextDialer, error) {
 	var auth *proxy.Auth
 	if p.Username != "" {
@@ -117,8 +165,13 @@ func (p *ProxyConfig) createSOCK5Dialer() (proxy.ContextDialer, error) {
 		}
 	}
 
+	timeout := p.DialTimeout
+	if timeout <= 0 {
+		timeout = defaultSOCKS5DialTimeout
+	}
+
 	dialer, err := proxy.SOCKS5("tcp", p.Address(), auth, &net.Dialer{
-		Timeout:   30 * time.Second,
+		Timeout:   timeout,
 		KeepAlive: 30 * time.Second,
 	})
 	if err != nil {
@@ -143,16 +196,7 @@ func (r *Request) Proxy(config *ProxyConfig) *Request {
 		return r
 	}
 
-	client := r.client
-	if client == nil {
-		client = &http.Client{}
-	} else {
-		client = &http.Client{
-			CheckRedirect: client.CheckRedirect,
-			Jar:           client.Jar,
-			Timeout:       client.Timeout,
-		}
-	}
+	client := r.cloneClient()
 
 	transport, err := config.CreateTransport(getTransport(client))
 	if err != nil {
@@ -165,6 +209,33 @@ func (r *Request) Proxy(config *ProxyConfig) *Request {
 	return r
 }
 
+// ProxyFromEnvironment creates a new request using the proxy from the environment
+func ProxyFromEnvironment() *Request {
+	return New().ProxyFromEnvironment()
+}
+
+// ProxyFromEnvironment selects the proxy from the HTTP_PROXY, HTTPS_PROXY and
+// NO_PROXY environment variables (or their lowercase versions) using
+// http.ProxyFromEnvironment, which reads them once per process.
+func (r *Request) ProxyFromEnvironment() *Request {
+	if r.err != nil {
+		return r
+	}
+
+	client := r.cloneClient()
+
+	transport := getTransport(client)
+	if transport == nil {
+		transport = http.DefaultTransport.(*http.Transport)
+	}
+	transport = transport.Clone()
+	transport.Proxy = http.ProxyFromEnvironment
+
+	client.Transport = transport
+	r.client = client
+	return r
+}
+
 // getTransport extracts transport from client
 func getTransport(client *http.Client) *http.Transport {
 	if client.Transport == nil {
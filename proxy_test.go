@@ -1,6 +1,17 @@
 package rq
 
-import "testing"
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+)
 
 func TestProxyFromURL(t *testing.T) {
 	tests := map[string]struct {
@@ -143,3 +154,129 @@ func proxyConfigEqual(a, b *ProxyConfig) bool {
 		a.Username == b.Username &&
 		a.Password == b.Password
 }
+
+// socks5Server is a minimal no-auth SOCKS5 proxy recording the targets it connects to
+type socks5Server struct {
+	ln      net.Listener
+	mu      sync.Mutex
+	targets []string
+}
+
+func newSOCKS5Server(t *testing.T) *socks5Server {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s := &socks5Server{ln: ln}
+	go s.serve()
+	t.Cleanup(func() { ln.Close() })
+
+	return s
+}
+
+func (s *socks5Server) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		go s.handle(conn)
+	}
+}
+
+func (s *socks5Server) handle(conn net.Conn) {
+	defer conn.Close()
+
+	// Greeting: version, method count, methods
+	header := make([]byte, 2)
+	if _, err := io.ReadFull(conn, header); err != nil {
+		return
+	}
+	if _, err := io.ReadFull(conn, make([]byte, header[1])); err != nil {
+		return
+	}
+	conn.Write([]byte{0x05, 0x00})
+
+	// Request: version, CONNECT, reserved, address type
+	req := make([]byte, 4)
+	if _, err := io.ReadFull(conn, req); err != nil {
+		return
+	}
+
+	var host string
+	switch req[3] {
+	case 0x01:
+		ip := make([]byte, 4)
+		io.ReadFull(conn, ip)
+		host = net.IP(ip).String()
+	case 0x03:
+		n := make([]byte, 1)
+		io.ReadFull(conn, n)
+		name := make([]byte, n[0])
+		io.ReadFull(conn, name)
+		host = string(name)
+	default:
+		return
+	}
+
+	port := make([]byte, 2)
+	if _, err := io.ReadFull(conn, port); err != nil {
+		return
+	}
+	target := net.JoinHostPort(host, strconv.Itoa(int(binary.BigEndian.Uint16(port))))
+
+	s.mu.Lock()
+	s.targets = append(s.targets, target)
+	s.mu.Unlock()
+
+	upstream, err := net.Dial("tcp", target)
+	if err != nil {
+		conn.Write([]byte{0x05, 0x05, 0x00, 0x01, 0, 0, 0, 0, 0, 0})
+		return
+	}
+	defer upstream.Close()
+
+	conn.Write([]byte{0x05, 0x00, 0x00, 0x01, 0, 0, 0, 0, 0, 0})
+
+	go io.Copy(upstream, conn)
+	io.Copy(conn, upstream)
+}
+
+func TestSOCKS5ProxyHTTPS(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("secure"))
+	}))
+	defer srv.Close()
+
+	socks := newSOCKS5Server(t)
+	host, port, _ := net.SplitHostPort(socks.ln.Addr().String())
+
+	// A custom TLS dialer on the base transport must not bypass the proxy
+	base := srv.Client().Transport.(*http.Transport).Clone()
+	base.DialTLSContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return nil, errors.New("direct TLS dial")
+	}
+
+	resp := Get(srv.URL).
+		Client(&http.Client{Transport: base}).
+		Proxy(&ProxyConfig{Type: ProxyTypeSOCKS5, Host: host, Port: port}).
+		Do()
+	if resp.Error() != nil {
+		t.Fatal(resp.Error())
+	}
+
+	body, _ := resp.String()
+	if body != "secure" {
+		t.Errorf("want body %q, got %q", "secure", body)
+	}
+	if resp.TLS == nil {
+		t.Error("want TLS connection state from the origin")
+	}
+
+	socks.mu.Lock()
+	defer socks.mu.Unlock()
+	if len(socks.targets) != 1 || socks.targets[0] != srv.Listener.Addr().String() {
+		t.Errorf("want one tunnel to %s, got %v", srv.Listener.Addr(), socks.targets)
+	}
+}
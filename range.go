@@ -0,0 +1,108 @@
+package rq
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// Range creates a new request for a byte range
+func Range(start, end int64) *Request {
+	return New().Range(start, end)
+}
+
+// Range sets the Range header to request bytes start through end inclusive.
+// A negative end requests everything from start onwards.
+func (r *Request) Range(start, end int64) *Request {
+	if r.err != nil {
+		return r
+	}
+
+	if end < 0 {
+		r.header().Set("Range", fmt.Sprintf("bytes=%d-", start))
+	} else {
+		r.header().Set("Range", fmt.Sprintf("bytes=%d-%d", start, end))
+	}
+	return r
+}
+
+// IfRange creates a new request with an If-Range header
+func IfRange(etagOrDate string) *Request {
+	return New().IfRange(etagOrDate)
+}
+
+// IfRange sets the If-Range header so the range is only served if the
+// resource still matches the given ETag or Last-Modified date
+func (r *Request) IfRange(etagOrDate string) *Request {
+	if r.err != nil {
+		return r
+	}
+	r.header().Set("If-Range", etagOrDate)
+	return r
+}
+
+// IsPartialContent returns true if the status code is 206
+func (r *Response) IsPartialContent() bool {
+	if r.err != nil || r.Response == nil {
+		return false
+	}
+	return r.StatusCode == http.StatusPartialContent
+}
+
+// ContentRange parses the Content-Range header.
+// total is -1 when the complete length is unknown ("*"). For an
+// unsatisfied range ("bytes */1234") start and end are -1.
+func (r *Response) ContentRange() (start, end, total int64, err error) {
+	if r.err != nil {
+		return 0, 0, 0, r.err
+	}
+	if r.Response == nil {
+		return 0, 0, 0, errors.New("no response")
+	}
+
+	header := r.Header.Get("Content-Range")
+	if header == "" {
+		return 0, 0, 0, errors.New("missing Content-Range header")
+	}
+
+	invalid := fmt.Errorf("invalid Content-Range %q", header)
+
+	spec, ok := strings.CutPrefix(header, "bytes ")
+	if !ok {
+		return 0, 0, 0, invalid
+	}
+
+	rng, size, ok := strings.Cut(spec, "/")
+	if !ok {
+		return 0, 0, 0, invalid
+	}
+
+	total = -1
+	if size != "*" {
+		if total, err = strconv.ParseInt(size, 10, 64); err != nil {
+			return 0, 0, 0, invalid
+		}
+	}
+
+	if rng == "*" {
+		return -1, -1, total, nil
+	}
+
+	first, last, ok := strings.Cut(rng, "-")
+	if !ok {
+		return 0, 0, 0, invalid
+	}
+	if start, err = strconv.ParseInt(first, 10, 64); err != nil {
+		return 0, 0, 0, invalid
+	}
+	if end, err = strconv.ParseInt(last, 10, 64); err != nil {
+		return 0, 0, 0, invalid
+	}
+	if end < start {
+		return 0, 0, 0, invalid
+	}
+
+	return start, end, total, nil
+}
@@ -0,0 +1,127 @@
+package rq
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestRangeRequest(t *testing.T) {
+	content := []byte("0123456789")
+	modTime := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("ETag", `"v1"`)
+		http.ServeContent(w, r, "file.txt", modTime, bytes.NewReader(content))
+	}))
+	defer srv.Close()
+
+	tests := map[string]struct {
+		req         *Request
+		wantPartial bool
+		wantBody    string
+		wantStart   int64
+		wantEnd     int64
+	}{
+		"closed range": {
+			req:         Get(srv.URL).Range(2, 5),
+			wantPartial: true,
+			wantBody:    "2345",
+			wantStart:   2,
+			wantEnd:     5,
+		},
+		"open-ended range": {
+			req:         Get(srv.URL).Range(7, -1),
+			wantPartial: true,
+			wantBody:    "789",
+			wantStart:   7,
+			wantEnd:     9,
+		},
+		"matching If-Range": {
+			req:         Get(srv.URL).Range(0, 1).IfRange(`"v1"`),
+			wantPartial: true,
+			wantBody:    "01",
+			wantStart:   0,
+			wantEnd:     1,
+		},
+		"stale If-Range": {
+			req:         Get(srv.URL).Range(0, 1).IfRange(`"v0"`),
+			wantPartial: false,
+			wantBody:    "0123456789",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := tt.req.Do()
+			if resp.Error() != nil {
+				t.Fatal(resp.Error())
+			}
+
+			if resp.IsPartialContent() != tt.wantPartial {
+				t.Fatalf("want partial %v, got status %d", tt.wantPartial, resp.StatusCode)
+			}
+
+			body, _ := resp.String()
+			if body != tt.wantBody {
+				t.Errorf("want body %q, got %q", tt.wantBody, body)
+			}
+
+			if !tt.wantPartial {
+				return
+			}
+
+			start, end, total, err := resp.ContentRange()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if start != tt.wantStart || end != tt.wantEnd || total != int64(len(content)) {
+				t.Errorf("want %d-%d/%d, got %d-%d/%d", tt.wantStart, tt.wantEnd, len(content), start, end, total)
+			}
+		})
+	}
+}
+
+func TestContentRange(t *testing.T) {
+	tests := map[string]struct {
+		header    string
+		wantStart int64
+		wantEnd   int64
+		wantTotal int64
+		wantErr   bool
+	}{
+		"full":             {header: "bytes 0-499/1234", wantStart: 0, wantEnd: 499, wantTotal: 1234},
+		"unknown total":    {header: "bytes 10-19/*", wantStart: 10, wantEnd: 19, wantTotal: -1},
+		"unsatisfied":      {header: "bytes */1234", wantStart: -1, wantEnd: -1, wantTotal: 1234},
+		"missing":          {header: "", wantErr: true},
+		"wrong unit":       {header: "items 0-1/2", wantErr: true},
+		"missing total":    {header: "bytes 0-1", wantErr: true},
+		"end before start": {header: "bytes 5-1/10", wantErr: true},
+		"not a number":     {header: "bytes a-b/10", wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := &Response{Response: &http.Response{Header: http.Header{}}}
+			if tt.header != "" {
+				resp.Header.Set("Content-Range", tt.header)
+			}
+
+			start, end, total, err := resp.ContentRange()
+			if tt.wantErr {
+				if err == nil {
+					t.Error("want error, got nil")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if start != tt.wantStart || end != tt.wantEnd || total != tt.wantTotal {
+				t.Errorf("want %d-%d/%d, got %d-%d/%d", tt.wantStart, tt.wantEnd, tt.wantTotal, start, end, total)
+			}
+		})
+	}
+}
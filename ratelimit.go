@@ -0,0 +1,49 @@
+package rq
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"golang.org/x/time/rate"
+)
+
+// RateLimitMiddleware throttles requests with a token bucket allowing limit
+// requests per second with the given burst. All requests built with the
+// returned middleware share one limiter. Requests wait for a token before
+// being sent; if the request context ends first, the context error is
+// returned on the response.
+func RateLimitMiddleware(limit rate.Limit, burst int) Middleware {
+	limiter := rate.NewLimiter(limit, burst)
+
+	return func(r *Request) *Request {
+		if r.err != nil {
+			return r
+		}
+
+		r.beforeSend = append(r.beforeSend, func(req *http.Request) error {
+			return waitLimiter(req.Context(), limiter)
+		})
+
+		return r
+	}
+}
+
+// waitLimiter blocks until limiter grants a token or ctx ends
+func waitLimiter(ctx context.Context, limiter *rate.Limiter) error {
+	err := limiter.Wait(ctx)
+	if err == nil {
+		return nil
+	}
+
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+
+	// Wait fails early when the token would not be available before the deadline
+	if _, ok := ctx.Deadline(); ok {
+		return fmt.Errorf("%w: %w", context.DeadlineExceeded, err)
+	}
+
+	return fmt.Errorf("rate limit: %w", err)
+}
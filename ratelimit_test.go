@@ -0,0 +1,70 @@
+package rq
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+func TestRateLimitMiddleware(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	middleware := RateLimitMiddleware(rate.Every(50*time.Millisecond), 1)
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		resp := Get(srv.URL).Use(middleware).Do()
+		if resp.Error() != nil {
+			t.Fatal(resp.Error())
+		}
+	}
+
+	if elapsed := time.Since(start); elapsed < 100*time.Millisecond {
+		t.Errorf("want requests to be throttled to >= 100ms, took %v", elapsed)
+	}
+}
+
+func TestRateLimitMiddlewareContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	t.Run("canceled while waiting", func(t *testing.T) {
+		middleware := RateLimitMiddleware(rate.Every(time.Hour), 1)
+		Get(srv.URL).Use(middleware).Do()
+
+		ctx, cancel := context.WithCancel(context.Background())
+		time.AfterFunc(20*time.Millisecond, cancel)
+
+		start := time.Now()
+		resp := Get(srv.URL).Use(middleware).Do(ctx)
+		if !errors.Is(resp.Error(), context.Canceled) {
+			t.Errorf("want context.Canceled, got %v", resp.Error())
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("want prompt return, took %v", elapsed)
+		}
+	})
+
+	t.Run("deadline before next token", func(t *testing.T) {
+		middleware := RateLimitMiddleware(rate.Every(time.Hour), 1)
+		Get(srv.URL).Use(middleware).Do()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		resp := Get(srv.URL).Use(middleware).Do(ctx)
+		if !errors.Is(resp.Error(), context.DeadlineExceeded) {
+			t.Errorf("want context.DeadlineExceeded, got %v", resp.Error())
+		}
+	})
+}
@@ -0,0 +1,115 @@
+package rq
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// maxRedirects matches the limit used by http.Client's default policy
+const maxRedirects = 10
+
+// ErrTooManyRedirects is returned when a request exceeds MaxRedirects
+var ErrTooManyRedirects = errors.New("too many redirects")
+
+// RedirectFunc decides whether to follow a redirect and may modify the
+// next request. It has the same semantics as http.Client.CheckRedirect.
+type RedirectFunc func(req *http.Request, via []*http.Request) error
+
+// OnRedirect creates a new request with a custom redirect policy
+func OnRedirect(fn RedirectFunc) *Request {
+	return New().OnRedirect(fn)
+}
+
+// OnRedirect sets a custom redirect policy on a copy of the request client.
+// The function replaces the default policy, including its 10-redirect limit.
+func (r *Request) OnRedirect(fn RedirectFunc) *Request {
+	if r.err != nil {
+		return r
+	}
+
+	client := r.cloneClient()
+	client.CheckRedirect = fn
+	r.client = client
+	return r
+}
+
+// FollowRedirects creates a new request that follows redirects or not
+func FollowRedirects(follow bool) *Request {
+	return New().FollowRedirects(follow)
+}
+
+// FollowRedirects controls whether redirects are followed. When disabled,
+// the 3xx response is returned as-is, with its Location header available.
+// Enabling it restores the default policy of up to 10 redirects.
+func (r *Request) FollowRedirects(follow bool) *Request {
+	if follow {
+		return r.OnRedirect(nil)
+	}
+	return r.OnRedirect(func(*http.Request, []*http.Request) error {
+		return http.ErrUseLastResponse
+	})
+}
+
+// MaxRedirects creates a new request that follows at most n redirects
+func MaxRedirects(n int) *Request {
+	return New().MaxRedirects(n)
+}
+
+// MaxRedirects limits the number of redirects followed to n, failing with
+// ErrTooManyRedirects beyond that. A policy set earlier with OnRedirect
+// still runs for every redirect within the limit.
+func (r *Request) MaxRedirects(n int) *Request {
+	if r.err != nil {
+		return r
+	}
+
+	var next RedirectFunc
+	if r.client != nil {
+		next = r.client.CheckRedirect
+	}
+
+	return r.OnRedirect(func(req *http.Request, via []*http.Request) error {
+		if len(via) > n {
+			return fmt.Errorf("stopped after %d redirects: %w", n, ErrTooManyRedirects)
+		}
+		if next != nil {
+			return next(req, via)
+		}
+		return nil
+	})
+}
+
+// StripAuthOnCrossHostRedirect returns a redirect policy that removes the
+// Authorization header when a redirect leads to a different host than the
+// original request. It stops after 10 redirects like the default policy.
+func StripAuthOnCrossHostRedirect() RedirectFunc {
+	return func(req *http.Request, via []*http.Request) error {
+		if len(via) >= maxRedirects {
+			return errors.New("stopped after 10 redirects")
+		}
+
+		if len(via) > 0 && req.URL.Host != via[0].URL.Host {
+			req.Header.Del("Authorization")
+		}
+
+		return nil
+	}
+}
+
+// FinalURL returns the URL of the request that produced the response, which
+// differs from the requested URL when redirects were followed
+func (r *Response) FinalURL() string {
+	if r.err != nil || r.Response == nil || r.Request == nil || r.Request.URL == nil {
+		return ""
+	}
+	return r.Request.URL.String()
+}
+
+// Redirected reports whether the response was reached by following a redirect
+func (r *Response) Redirected() bool {
+	if r.err != nil || r.Response == nil || r.Request == nil {
+		return false
+	}
+	return r.Request.Response != nil
+}
@@ -0,0 +1,226 @@
+package rq
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestOnRedirect(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/start" {
+			http.Redirect(w, r, "/end", http.StatusFound)
+			return
+		}
+		w.Write([]byte(r.Header.Get("X-Redirected")))
+	}))
+	defer srv.Close()
+
+	t.Run("modifies request", func(t *testing.T) {
+		resp := Get(srv.URL + "/start").
+			OnRedirect(func(req *http.Request, via []*http.Request) error {
+				req.Header.Set("X-Redirected", "yes")
+				return nil
+			}).
+			Do()
+		if resp.Error() != nil {
+			t.Fatal(resp.Error())
+		}
+
+		body, _ := resp.String()
+		if body != "yes" {
+			t.Errorf("want redirect callback to set header, got %q", body)
+		}
+	})
+
+	t.Run("returns error", func(t *testing.T) {
+		wantErr := errors.New("no redirects")
+		resp := Get(srv.URL + "/start").
+			OnRedirect(func(req *http.Request, via []*http.Request) error {
+				return wantErr
+			}).
+			Do()
+		if !errors.Is(resp.Error(), wantErr) {
+			t.Errorf("want %v, got %v", wantErr, resp.Error())
+		}
+	})
+
+	t.Run("does not modify shared client", func(t *testing.T) {
+		client := &http.Client{}
+		Get(srv.URL).Client(client).OnRedirect(StripAuthOnCrossHostRedirect())
+		if client.CheckRedirect != nil {
+			t.Error("want original client to be left untouched")
+		}
+	})
+}
+
+func TestStripAuthOnCrossHostRedirect(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Header.Get("Authorization")))
+	}))
+	defer target.Close()
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/cross":
+			http.Redirect(w, r, target.URL, http.StatusFound)
+		case "/same":
+			http.Redirect(w, r, "/echo", http.StatusFound)
+		default:
+			w.Write([]byte(r.Header.Get("Authorization")))
+		}
+	}))
+	defer srv.Close()
+
+	tests := map[string]struct {
+		path     string
+		wantAuth string
+	}{
+		"cross host strips auth": {
+			path:     "/cross",
+			wantAuth: "",
+		},
+		"same host keeps auth": {
+			path:     "/same",
+			wantAuth: "Bearer secret",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := Get(srv.URL + tt.path).
+				BearerToken("secret").
+				OnRedirect(StripAuthOnCrossHostRedirect()).
+				Do()
+			if resp.Error() != nil {
+				t.Fatal(resp.Error())
+			}
+
+			body, _ := resp.String()
+			if body != tt.wantAuth {
+				t.Errorf("want Authorization %q, got %q", tt.wantAuth, body)
+			}
+		})
+	}
+}
+
+func TestRedirectControl(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/a":
+			http.Redirect(w, r, "/b", http.StatusFound)
+		case "/b":
+			http.Redirect(w, r, "/c", http.StatusFound)
+		default:
+			w.Write([]byte(r.Header.Get("X-Seen")))
+		}
+	}))
+	defer srv.Close()
+
+	t.Run("FollowRedirects false", func(t *testing.T) {
+		resp := Get(srv.URL + "/a").FollowRedirects(false).Do()
+		if resp.Error() != nil {
+			t.Fatal(resp.Error())
+		}
+		if resp.StatusCode != http.StatusFound {
+			t.Errorf("want status 302, got %d", resp.StatusCode)
+		}
+		if got := resp.Header.Get("Location"); got != "/b" {
+			t.Errorf("want Location /b, got %q", got)
+		}
+	})
+
+	t.Run("FollowRedirects true", func(t *testing.T) {
+		resp := Get(srv.URL + "/a").FollowRedirects(false).FollowRedirects(true).Do()
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("want status 200, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("MaxRedirects within limit", func(t *testing.T) {
+		resp := Get(srv.URL + "/a").MaxRedirects(2).Do()
+		if resp.Error() != nil {
+			t.Fatal(resp.Error())
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("want status 200, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("MaxRedirects exceeded", func(t *testing.T) {
+		resp := Get(srv.URL + "/a").MaxRedirects(1).Do()
+		if !errors.Is(resp.Error(), ErrTooManyRedirects) {
+			t.Errorf("want ErrTooManyRedirects, got %v", resp.Error())
+		}
+	})
+
+	t.Run("MaxRedirects keeps OnRedirect", func(t *testing.T) {
+		got, err := Get(srv.URL + "/a").
+			OnRedirect(func(req *http.Request, via []*http.Request) error {
+				req.Header.Set("X-Seen", "yes")
+				return nil
+			}).
+			MaxRedirects(5).
+			Do().String()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != "yes" {
+			t.Errorf("want OnRedirect to run, got %q", got)
+		}
+	})
+
+	t.Run("does not modify default client", func(t *testing.T) {
+		FollowRedirects(false)
+		MaxRedirects(1)
+		if defaultClient.CheckRedirect != nil {
+			t.Error("want default client to be left untouched")
+		}
+	})
+}
+
+func TestFinalURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/old" {
+			http.Redirect(w, r, "/new?page=1", http.StatusMovedPermanently)
+			return
+		}
+	}))
+	defer srv.Close()
+
+	tests := map[string]struct {
+		req            *Request
+		wantURL        string
+		wantRedirected bool
+	}{
+		"followed": {
+			req:            Get(srv.URL + "/old"),
+			wantURL:        srv.URL + "/new?page=1",
+			wantRedirected: true,
+		},
+		"not followed": {
+			req:     Get(srv.URL + "/old").FollowRedirects(false),
+			wantURL: srv.URL + "/old",
+		},
+		"no redirect": {
+			req:     Get(srv.URL + "/new"),
+			wantURL: srv.URL + "/new",
+		},
+		"failed request": {
+			req: New(),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := tt.req.Do()
+			if got := resp.FinalURL(); got != tt.wantURL {
+				t.Errorf("want final URL %q, got %q", tt.wantURL, got)
+			}
+			if got := resp.Redirected(); got != tt.wantRedirected {
+				t.Errorf("want redirected %v, got %v", tt.wantRedirected, got)
+			}
+		})
+	}
+}
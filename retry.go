@@ -3,20 +3,107 @@ package rq
 import (
 	"bytes"
 	"context"
+	crand "crypto/rand"
+	"encoding/json"
+	"fmt"
 	"io"
 	"math"
 	"math/rand"
+	"net/http"
+	"net/http/httptrace"
+	"sync/atomic"
 	"time"
 )
 
+// JitterStrategy selects how retry delays are randomized
+type JitterStrategy int
+
+const (
+	// JitterNone leaves delays as computed, unless the Jitter flag is set
+	JitterNone JitterStrategy = iota
+	// JitterFull waits a random time between zero and the delay
+	JitterFull
+	// JitterEqual waits half the delay plus a random time up to the other half
+	JitterEqual
+	// JitterDecorrelated waits a random time between the base delay and three
+	// times the previous delay, capped at MaxDelay
+	JitterDecorrelated
+)
+
 // RetryConfig defines retry behavior
 type RetryConfig struct {
+	// MaxAttempts is the total number of attempts, including the first.
+	// Values below 1 are treated as 1.
 	MaxAttempts int
-	Delay       time.Duration
-	MaxDelay    time.Duration
-	Multiplier  float64
-	Jitter      bool
-	RetryIf     func(*Response) bool
+
+	Delay      time.Duration
+	MaxDelay   time.Duration
+	Multiplier float64
+	Jitter     bool
+
+	// RetryIf reports whether a response calls for another attempt. With
+	// DoWithRetry the body is already buffered, so Bytes, String and JSON
+	// can be used freely and every attempt gets its own response.
+	RetryIf func(*Response) bool
+
+	// Backoff returns the delay after the given zero-based attempt.
+	// When set it replaces the Delay, Multiplier, MaxDelay and Jitter settings.
+	Backoff func(attempt int) time.Duration
+
+	// JitterStrategy randomizes delays to keep clients from retrying in
+	// lockstep, following the algorithms recommended by AWS. It also applies
+	// to Backoff; JitterDecorrelated uses Backoff(0) or Delay as its base.
+	// When set it takes precedence over Jitter, which adds up to 30%.
+	JitterStrategy JitterStrategy
+
+	// PerAttemptTimeout bounds each attempt separately from ctx, so one
+	// hanging attempt cannot use up the whole budget. An attempt that times
+	// out is retried like any other failed request. With RetryTransport the
+	// timeout also covers reading the body that is returned. Zero means no
+	// per-attempt timeout.
+	PerAttemptTimeout time.Duration
+
+	// MaxElapsed caps the total time spent across attempts, including delays.
+	// No retry is started if its delay would go past the cap; the last
+	// response is returned instead. Zero means no cap.
+	MaxElapsed time.Duration
+
+	// IdempotentOnly restricts retries of requests that are not idempotent,
+	// such as POST and PATCH, to failures that happened before any of the
+	// request was sent, so a server that already acted on a request does
+	// not see it twice. GET, HEAD, PUT, DELETE, OPTIONS and TRACE, and
+	// requests carrying an Idempotency-Key header, are retried as usual.
+	IdempotentOnly bool
+
+	// IdempotencyKey sends a random Idempotency-Key header, the same on every
+	// attempt, with requests that are not idempotent and do not set one, so
+	// the server can deduplicate them. Such requests then count as idempotent.
+	IdempotencyKey bool
+
+	// RetryOnValidationFailure also retries responses that RetryIf accepts
+	// but that fail the request's validators, e.g. an eventually consistent
+	// API answering 200 with data that is not ready yet. Validators always
+	// run on the response that is finally returned.
+	RetryOnValidationFailure bool
+
+	// OnRetry, if set, is called before sleeping ahead of a retry with the
+	// one-based number of the failed attempt, its response and the delay.
+	OnRetry func(attempt int, resp *Response, nextDelay time.Duration)
+}
+
+// NewRetryConfig creates a retry configuration using a backoff function such as
+// ExponentialBackoff, LinearBackoff or ConstantBackoff. A nil retryIf retries
+// on network errors, 5xx and 429 responses.
+func NewRetryConfig(maxAttempts int, backoff func(int) time.Duration, retryIf func(*Response) bool) *RetryConfig {
+	if retryIf == nil {
+		retryIf = defaultRetryIf
+	}
+
+	return &RetryConfig{
+		MaxAttempts: maxAttempts,
+		RetryIf:     retryIf,
+		Backoff:     backoff,
+	}
 }
 
 // DefaultRetryConfig returns a default retry configuration
@@ -39,6 +126,53 @@ func defaultRetryIf(resp *Response) bool {
 	return resp.StatusCode >= 500 || resp.StatusCode == 429
 }
 
+// RetryWhenBodyContains returns a RetryIf predicate that retries responses
+// whose body contains substr, such as an error envelope sent with status 200
+func RetryWhenBodyContains(substr string) func(*Response) bool {
+	return func(resp *Response) bool {
+		if resp.Response == nil {
+			return false
+		}
+		return bytes.Contains(resp.body, []byte(substr))
+	}
+}
+
+// RetryWhenJSON returns a RetryIf predicate that decodes a JSON object body
+// and retries when fn returns true. Bodies that are not a JSON object are
+// not retried.
+func RetryWhenJSON(fn func(map[string]any) bool) func(*Response) bool {
+	return func(resp *Response) bool {
+		if resp.Response == nil {
+			return false
+		}
+
+		var body map[string]any
+		if err := json.Unmarshal(resp.body, &body); err != nil || body == nil {
+			return false
+		}
+		return fn(body)
+	}
+}
+
+// Retry creates a new request that is retried according to config
+func Retry(config *RetryConfig) *Request {
+	return New().Retry(config)
+}
+
+// Retry makes Do and DoContext retry the request according to config, as
+// DoWithRetry does, so retries compose with validators and middleware.
+// A nil config uses DefaultRetryConfig(). DoStream and SSE do not retry.
+func (r *Request) Retry(config *RetryConfig) *Request {
+	if r.err != nil {
+		return r
+	}
+	if config == nil {
+		config = DefaultRetryConfig()
+	}
+	r.retry = config
+	return r
+}
+
 // DoWithRetry executes the request with retry logic
 func (r *Request) DoWithRetry(ctx context.Context, config *RetryConfig) *Response {
 	if config == nil {
@@ -49,6 +183,17 @@ func (r *Request) DoWithRetry(ctx context.Context, config *RetryConfig) *Respons
 		return &Response{err: r.err}
 	}
 
+	ctx = r.resolveContext(ctx)
+
+	if config.IdempotencyKey && !isIdempotent(r.method, r.requestHeader()) {
+		key, err := newUUID()
+		if err != nil {
+			return &Response{err: err}
+		}
+		r = r.Clone()
+		r.header().Set("Idempotency-Key", key)
+	}
+
 	// Read body into memory so we can retry
 	var bodyBytes []byte
 	if r.body != nil {
@@ -59,44 +204,299 @@ func (r *Request) DoWithRetry(ctx context.Context, config *RetryConfig) *Respons
 		}
 	}
 
+	// Validators run here rather than in do so that a validation failure
+	// can be told apart from a failed request
+	send := *r
+	send.validators = nil
+
 	var resp *Response
-	delay := config.Delay
+	var validated bool
+	var delay time.Duration
+	var attemptErrors []error
+	start := time.Now()
+
+	finish := func(attempts int) *Response {
+		if !validated && resp.err == nil {
+			resp = r.runValidators(resp)
+		}
+		resp.attempts = attempts
+		resp.attemptErrors = attemptErrors
+		return resp
+	}
 
-	for attempt := 0; attempt < config.MaxAttempts; attempt++ {
+	maxAttempts := max(config.MaxAttempts, 1)
+	for attempt := 0; ; attempt++ {
 		if bodyBytes != nil {
-			r.body = bytes.NewReader(bodyBytes)
+			send.body = bytes.NewReader(bodyBytes)
 		}
 
-		resp = r.DoContext(ctx)
+		var sent atomic.Bool
+		attemptCtx, cancel := config.attemptContext(ctx)
+		resp = send.do(withSentTrace(attemptCtx, &sent), false)
+		cancel()
+		validated = false
 
-		if !config.RetryIf(resp) {
-			return resp
+		header := r.requestHeader()
+		if !config.shouldRetry(resp, r.method, header, sent.Load()) {
+			if resp.err != nil {
+				return finish(attempt + 1)
+			}
+			resp, validated = r.runValidators(resp), true
+			if resp.err == nil || !config.RetryOnValidationFailure || !config.retryAllowed(resp, r.method, header, sent.Load()) {
+				return finish(attempt + 1)
+			}
 		}
+		attemptErrors = append(attemptErrors, attemptError(resp))
 
-		if attempt == config.MaxAttempts-1 {
-			break
+		if attempt == maxAttempts-1 {
+			return finish(attempt + 1)
 		}
 
-		if config.Jitter {
-			delay = addJitter(delay)
+		delay = config.backoff(attempt, delay)
+		if config.exceedsMaxElapsed(start, delay) {
+			return finish(attempt + 1)
+		}
+		if config.OnRetry != nil {
+			config.OnRetry(attempt+1, resp, delay)
 		}
 
 		select {
 		case <-ctx.Done():
 			resp.err = ctx.Err()
-			return resp
+			return finish(attempt + 1)
 		case <-time.After(delay):
 		}
+	}
+}
+
+// Attempts returns how many attempts DoWithRetry made, or 0 for responses
+// that did not come from DoWithRetry
+func (r *Response) Attempts() int {
+	return r.attempts
+}
+
+// AttemptErrors returns why each attempt made by DoWithRetry was considered
+// failed, in order. A response that eventually succeeded has one error less
+// than Attempts; non-retry responses have none.
+func (r *Response) AttemptErrors() []error {
+	return r.attemptErrors
+}
 
-		delay = time.Duration(float64(delay) * config.Multiplier)
-		if delay > config.MaxDelay {
-			delay = config.MaxDelay
+// attemptError describes why resp was retried
+func attemptError(resp *Response) error {
+	if err := resp.AsHTTPError(); err != nil {
+		return err
+	}
+	return fmt.Errorf("retry condition matched with status %s", resp.Status)
+}
+
+// shouldRetry reports whether resp calls for another attempt. sent tells
+// whether any of the request reached the connection.
+func (c *RetryConfig) shouldRetry(resp *Response, method string, header http.Header, sent bool) bool {
+	return c.RetryIf(resp) && c.retryAllowed(resp, method, header, sent)
+}
+
+// retryAllowed applies IdempotentOnly to a failed attempt
+func (c *RetryConfig) retryAllowed(resp *Response, method string, header http.Header, sent bool) bool {
+	if !c.IdempotentOnly || isIdempotent(method, header) {
+		return true
+	}
+	return resp.err != nil && !sent
+}
+
+// isIdempotent reports whether a request can safely be sent twice, following
+// the rules net/http uses for its own retries
+func isIdempotent(method string, header http.Header) bool {
+	switch method {
+	case "", http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete, http.MethodOptions, http.MethodTrace:
+		return true
+	}
+	return header.Get("Idempotency-Key") != "" || header.Get("X-Idempotency-Key") != ""
+}
+
+// withSentTrace records in sent once the first request header is written
+func withSentTrace(ctx context.Context, sent *atomic.Bool) context.Context {
+	return httptrace.WithClientTrace(ctx, &httptrace.ClientTrace{
+		WroteHeaderField: func(string, []string) {
+			sent.Store(true)
+		},
+	})
+}
+
+// newUUID returns a random version 4 UUID
+func newUUID() (string, error) {
+	var b [16]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		return "", fmt.Errorf("generate UUID: %w", err)
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16]), nil
+}
+
+// backoff returns the delay after the given zero-based attempt. prev is the
+// delay returned for the previous attempt, used by JitterDecorrelated.
+func (c *RetryConfig) backoff(attempt int, prev time.Duration) time.Duration {
+	if c.JitterStrategy == JitterDecorrelated {
+		return c.decorrelatedBackoff(prev)
+	}
+
+	var delay time.Duration
+	if c.Backoff != nil {
+		delay = c.Backoff(attempt)
+	} else {
+		delay = c.Delay
+		for i := 0; i < attempt; i++ {
+			delay = time.Duration(float64(delay) * c.Multiplier)
+			if delay > c.MaxDelay {
+				delay = c.MaxDelay
+			}
+		}
+	}
+
+	switch c.JitterStrategy {
+	case JitterFull:
+		delay = randomDuration(0, delay)
+	case JitterEqual:
+		delay = delay/2 + randomDuration(0, delay-delay/2)
+	default:
+		if c.Jitter && c.Backoff == nil {
+			delay = addJitter(delay)
 		}
 	}
 
+	return delay
+}
+
+// decorrelatedBackoff returns a random delay between the base delay and
+// three times prev, capped at MaxDelay
+func (c *RetryConfig) decorrelatedBackoff(prev time.Duration) time.Duration {
+	base := c.Delay
+	if c.Backoff != nil {
+		base = c.Backoff(0)
+	}
+	prev = max(prev, base)
+
+	delay := randomDuration(base, prev*3)
+	if c.MaxDelay > 0 && delay > c.MaxDelay {
+		delay = c.MaxDelay
+	}
+	return delay
+}
+
+// exceedsMaxElapsed reports whether waiting delay would go past MaxElapsed
+func (c *RetryConfig) exceedsMaxElapsed(start time.Time, delay time.Duration) bool {
+	return c.MaxElapsed > 0 && time.Since(start)+delay > c.MaxElapsed
+}
+
+// retryTransport retries round trips according to a RetryConfig
+type retryTransport struct {
+	base   http.RoundTripper
+	config *RetryConfig
+}
+
+// RetryTransport wraps base with retries so any http.Client benefits from them.
+// Request bodies are replayed with GetBody; requests with a body but no GetBody
+// are not retried. RetryIf sees the status and headers but not the body, so
+// RetryWhenBodyContains and RetryWhenJSON never match here.
+// A nil base uses http.DefaultTransport and a nil config DefaultRetryConfig().
+func RetryTransport(base http.RoundTripper, config *RetryConfig) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if config == nil {
+		config = DefaultRetryConfig()
+	}
+	return &retryTransport{base: base, config: config}
+}
+
+// RoundTrip implements the RoundTripper interface with retry support
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	hasBody := req.Body != nil && req.Body != http.NoBody
+	start := time.Now()
+	var delay time.Duration
+
+	if t.config.IdempotencyKey && !isIdempotent(req.Method, req.Header) {
+		key, err := newUUID()
+		if err != nil {
+			return nil, err
+		}
+		req = req.Clone(ctx)
+		req.Header.Set("Idempotency-Key", key)
+	}
+
+	for attempt := 0; ; attempt++ {
+		attemptReq := req
+		if attempt > 0 && hasBody {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, fmt.Errorf("replay request body: %w", err)
+			}
+			attemptReq = req.Clone(ctx)
+			attemptReq.Body = body
+		}
+
+		var sent atomic.Bool
+		attemptCtx, cancel := t.config.attemptContext(ctx)
+		resp, err := t.base.RoundTrip(attemptReq.WithContext(withSentTrace(attemptCtx, &sent)))
+
+		failed := &Response{Response: resp, err: err}
+		last := attempt >= t.config.MaxAttempts-1 || (hasBody && req.GetBody == nil)
+		if last || !t.config.shouldRetry(failed, req.Method, req.Header, sent.Load()) {
+			return releaseAttempt(resp, cancel), err
+		}
+
+		delay = t.config.backoff(attempt, delay)
+		if t.config.exceedsMaxElapsed(start, delay) {
+			return releaseAttempt(resp, cancel), err
+		}
+
+		if resp != nil {
+			_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxDiscardBytes))
+			_ = resp.Body.Close()
+		}
+		cancel()
+
+		if t.config.OnRetry != nil {
+			t.config.OnRetry(attempt+1, failed, delay)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// attemptContext derives the context for a single attempt
+func (c *RetryConfig) attemptContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	if c.PerAttemptTimeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, c.PerAttemptTimeout)
+}
+
+// releaseAttempt ties cancel to the returned body so the attempt context
+// lives until the caller is done reading
+func releaseAttempt(resp *http.Response, cancel context.CancelFunc) *http.Response {
+	if resp == nil {
+		cancel()
+		return nil
+	}
+	resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
 	return resp
 }
 
+// randomDuration returns a random duration in [lo, hi)
+func randomDuration(lo, hi time.Duration) time.Duration {
+	if hi <= lo {
+		return lo
+	}
+	return lo + time.Duration(rand.Int63n(int64(hi-lo)))
+}
+
 // addJitter adds random jitter to the delay
 func addJitter(delay time.Duration) time.Duration {
 	jitter := time.Duration(rand.Float64() * float64(delay) * 0.3)
@@ -2,8 +2,12 @@ package rq
 
 import (
 	"context"
+	"errors"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"sync/atomic"
 	"testing"
 	"time"
@@ -45,6 +49,74 @@ func TestRetryOnServerError(t *testing.T) {
 	if atomic.LoadInt32(&attempts) != 3 {
 		t.Errorf("want 3 attempts, got %d", attempts)
 	}
+
+	t.Run("below one", func(t *testing.T) {
+		for _, maxAttempts := range []int{0, -1} {
+			atomic.StoreInt32(&attempts, 0)
+
+			resp := Get(srv.URL).DoWithRetry(ctx, &RetryConfig{MaxAttempts: maxAttempts, RetryIf: defaultRetryIf})
+			if resp == nil {
+				t.Fatalf("want response for MaxAttempts %d, got nil", maxAttempts)
+			}
+			if resp.StatusCode != http.StatusInternalServerError {
+				t.Errorf("want status 500, got %d", resp.StatusCode)
+			}
+			if got := atomic.LoadInt32(&attempts); got != 1 {
+				t.Errorf("want 1 attempt for MaxAttempts %d, got %d", maxAttempts, got)
+			}
+		}
+	})
+}
+
+func TestRetryAttempts(t *testing.T) {
+	var calls int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/down":
+			w.WriteHeader(http.StatusBadGateway)
+		case atomic.AddInt32(&calls, 1) < 3:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer srv.Close()
+
+	config := &RetryConfig{MaxAttempts: 4, Delay: time.Millisecond, RetryIf: defaultRetryIf}
+
+	tests := map[string]struct {
+		path         string
+		wantAttempts int
+		wantErrors   []int
+	}{
+		"eventual success": {path: "/", wantAttempts: 3, wantErrors: []int{503, 503}},
+		"exhausted":        {path: "/down", wantAttempts: 4, wantErrors: []int{502, 502, 502, 502}},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := Get(srv.URL+tt.path).DoWithRetry(context.Background(), config)
+
+			if resp.Attempts() != tt.wantAttempts {
+				t.Errorf("want %d attempts, got %d", tt.wantAttempts, resp.Attempts())
+			}
+			if len(resp.AttemptErrors()) != len(tt.wantErrors) {
+				t.Fatalf("want %d attempt errors, got %v", len(tt.wantErrors), resp.AttemptErrors())
+			}
+			for i, err := range resp.AttemptErrors() {
+				var httpErr *HTTPError
+				if !errors.As(err, &httpErr) || httpErr.StatusCode != tt.wantErrors[i] {
+					t.Errorf("attempt %d: want HTTPError %d, got %v", i+1, tt.wantErrors[i], err)
+				}
+			}
+		})
+	}
+
+	t.Run("plain do", func(t *testing.T) {
+		resp := Get(srv.URL).Do()
+		if resp.Attempts() != 0 || resp.AttemptErrors() != nil {
+			t.Errorf("want zero attempts for Do, got %d %v", resp.Attempts(), resp.AttemptErrors())
+		}
+	})
 }
 
 func TestRetryOnRateLimit(t *testing.T) {
@@ -149,6 +221,69 @@ func TestRetryCustomConditions(t *testing.T) {
 	}
 }
 
+func TestRetryOnBody(t *testing.T) {
+	tests := map[string]struct {
+		retryIf func(*Response) bool
+	}{
+		"body contains": {
+			retryIf: RetryWhenBodyContains(`"retryable":true`),
+		},
+		"json predicate": {
+			retryIf: RetryWhenJSON(func(body map[string]any) bool {
+				return body["retryable"] == true
+			}),
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			var attempts int32
+
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if atomic.AddInt32(&attempts, 1) < 3 {
+					w.Write([]byte(`{"retryable":true}`))
+					return
+				}
+				w.Write([]byte(`{"id":1}`))
+			}))
+			defer srv.Close()
+
+			var seen []string
+			config := &RetryConfig{
+				MaxAttempts: 5,
+				Delay:       time.Millisecond,
+				RetryIf: func(resp *Response) bool {
+					body, _ := resp.String()
+					seen = append(seen, body)
+					return tc.retryIf(resp)
+				},
+			}
+
+			resp := Get(srv.URL).DoWithRetry(context.Background(), config)
+			if resp.Error() != nil {
+				t.Fatal(resp.Error())
+			}
+
+			if got := atomic.LoadInt32(&attempts); got != 3 {
+				t.Errorf("want 3 attempts, got %d", got)
+			}
+			if body, _ := resp.String(); body != `{"id":1}` {
+				t.Errorf("want final body {\"id\":1}, got %q", body)
+			}
+			if len(seen) != 3 || seen[0] != `{"retryable":true}` {
+				t.Errorf("want body visible to RetryIf on every attempt, got %q", seen)
+			}
+		})
+	}
+
+	t.Run("network error", func(t *testing.T) {
+		resp := &Response{err: errors.New("connection refused")}
+		if RetryWhenBodyContains("x")(resp) || RetryWhenJSON(func(map[string]any) bool { return true })(resp) {
+			t.Error("want no retry without a response")
+		}
+	})
+}
+
 func TestRetryWithJitter(t *testing.T) {
 	var attempts int32
 	var delays []time.Duration
@@ -285,6 +420,251 @@ func TestBackoffFunctions(t *testing.T) {
 	})
 }
 
+func TestRetryConfigBackoff(t *testing.T) {
+	tests := map[string]struct {
+		config *RetryConfig
+		want   []time.Duration
+	}{
+		"legacy fields": {
+			config: &RetryConfig{Delay: 10 * time.Millisecond, Multiplier: 2, MaxDelay: 30 * time.Millisecond},
+			want:   []time.Duration{10 * time.Millisecond, 20 * time.Millisecond, 30 * time.Millisecond},
+		},
+		"backoff overrides legacy fields": {
+			config: &RetryConfig{
+				Delay:      time.Second,
+				Multiplier: 10,
+				MaxDelay:   time.Minute,
+				Jitter:     true,
+				Backoff:    ConstantBackoff(5 * time.Millisecond),
+			},
+			want: []time.Duration{5 * time.Millisecond, 5 * time.Millisecond, 5 * time.Millisecond},
+		},
+		"exponential backoff": {
+			config: &RetryConfig{Backoff: ExponentialBackoff(time.Millisecond, 3, time.Second)},
+			want:   []time.Duration{time.Millisecond, 3 * time.Millisecond, 9 * time.Millisecond},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			for attempt, want := range tt.want {
+				if got := tt.config.backoff(attempt, 0); got != want {
+					t.Errorf("attempt %d: want delay %v, got %v", attempt, want, got)
+				}
+			}
+		})
+	}
+}
+
+func TestJitterStrategy(t *testing.T) {
+	base := &RetryConfig{
+		Delay:      100 * time.Millisecond,
+		MaxDelay:   time.Second,
+		Multiplier: 2,
+	}
+
+	tests := map[string]struct {
+		strategy JitterStrategy
+		jitter   bool
+		min, max func(attempt int, prev time.Duration) time.Duration
+	}{
+		"none": {
+			strategy: JitterNone,
+			min:      func(a int, _ time.Duration) time.Duration { return base.backoff(a, 0) },
+			max:      func(a int, _ time.Duration) time.Duration { return base.backoff(a, 0) },
+		},
+		"legacy flag": {
+			jitter: true,
+			min:    func(a int, _ time.Duration) time.Duration { return base.backoff(a, 0) },
+			max:    func(a int, _ time.Duration) time.Duration { return base.backoff(a, 0) * 13 / 10 },
+		},
+		"full": {
+			strategy: JitterFull,
+			min:      func(int, time.Duration) time.Duration { return 0 },
+			max:      func(a int, _ time.Duration) time.Duration { return base.backoff(a, 0) },
+		},
+		"equal": {
+			strategy: JitterEqual,
+			min:      func(a int, _ time.Duration) time.Duration { return base.backoff(a, 0) / 2 },
+			max:      func(a int, _ time.Duration) time.Duration { return base.backoff(a, 0) },
+		},
+		"decorrelated": {
+			strategy: JitterDecorrelated,
+			min:      func(int, time.Duration) time.Duration { return base.Delay },
+			max: func(_ int, prev time.Duration) time.Duration {
+				return min(max(prev, base.Delay)*3, base.MaxDelay)
+			},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			config := *base
+			config.JitterStrategy = tt.strategy
+			config.Jitter = tt.jitter
+
+			for run := 0; run < 50; run++ {
+				var prev time.Duration
+				for attempt := 0; attempt < 6; attempt++ {
+					got := config.backoff(attempt, prev)
+					if lo, hi := tt.min(attempt, prev), tt.max(attempt, prev); got < lo || got > hi {
+						t.Fatalf("attempt %d: want delay in [%v, %v], got %v", attempt, lo, hi, got)
+					}
+					prev = got
+				}
+			}
+		})
+	}
+}
+
+func TestNewRetryConfig(t *testing.T) {
+	var attempts int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	var calls []int
+	backoff := func(attempt int) time.Duration {
+		calls = append(calls, attempt)
+		return LinearBackoff(10*time.Millisecond, 10*time.Millisecond, time.Second)(attempt)
+	}
+
+	config := NewRetryConfig(3, backoff, nil)
+	if config.RetryIf == nil {
+		t.Fatal("want default RetryIf when nil is given")
+	}
+
+	start := time.Now()
+	resp := Get(srv.URL).DoWithRetry(context.Background(), config)
+	elapsed := time.Since(start)
+
+	if resp.Error() != nil {
+		t.Fatal(resp.Error())
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("want status 200, got %d", resp.StatusCode)
+	}
+
+	if len(calls) != 2 || calls[0] != 0 || calls[1] != 1 {
+		t.Errorf("want backoff called for attempts [0 1], got %v", calls)
+	}
+	if elapsed < 30*time.Millisecond {
+		t.Errorf("want backoff delays of 10ms+20ms to be applied, took %v", elapsed)
+	}
+}
+
+func TestRetryOnRetryHook(t *testing.T) {
+	var attempts int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	type retryCall struct {
+		attempt int
+		status  int
+		delay   time.Duration
+	}
+	var calls []retryCall
+
+	config := NewRetryConfig(3, LinearBackoff(time.Millisecond, time.Millisecond, time.Second), nil)
+	config.OnRetry = func(attempt int, resp *Response, nextDelay time.Duration) {
+		calls = append(calls, retryCall{attempt, resp.StatusCode, nextDelay})
+	}
+
+	t.Run("DoWithRetry", func(t *testing.T) {
+		resp := Get(srv.URL).DoWithRetry(context.Background(), config)
+		if resp.Error() != nil {
+			t.Fatal(resp.Error())
+		}
+
+		want := []retryCall{
+			{1, http.StatusBadGateway, time.Millisecond},
+			{2, http.StatusBadGateway, 2 * time.Millisecond},
+		}
+		if len(calls) != len(want) {
+			t.Fatalf("want %d OnRetry calls, got %d", len(want), len(calls))
+		}
+		for i := range want {
+			if calls[i] != want[i] {
+				t.Errorf("call %d: want %+v, got %+v", i, want[i], calls[i])
+			}
+		}
+	})
+
+	t.Run("RetryTransport", func(t *testing.T) {
+		atomic.StoreInt32(&attempts, 0)
+		calls = nil
+
+		client := &http.Client{Transport: RetryTransport(nil, config)}
+		resp := Get(srv.URL).Client(client).Do()
+		if resp.Error() != nil {
+			t.Fatal(resp.Error())
+		}
+		if len(calls) != 2 {
+			t.Errorf("want 2 OnRetry calls, got %d", len(calls))
+		}
+	})
+}
+
+func TestRetryMaxElapsed(t *testing.T) {
+	var attempts int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	tests := map[string]struct {
+		maxElapsed   time.Duration
+		wantAttempts int32
+	}{
+		"no cap": {
+			maxElapsed:   0,
+			wantAttempts: 4,
+		},
+		"cap stops before exceeding delay": {
+			maxElapsed:   70 * time.Millisecond,
+			wantAttempts: 2,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			atomic.StoreInt32(&attempts, 0)
+
+			config := NewRetryConfig(4, LinearBackoff(40*time.Millisecond, 40*time.Millisecond, time.Second), nil)
+			config.MaxElapsed = tt.maxElapsed
+
+			start := time.Now()
+			resp := Get(srv.URL).DoWithRetry(context.Background(), config)
+			elapsed := time.Since(start)
+
+			if resp.StatusCode != http.StatusServiceUnavailable {
+				t.Errorf("want last response with status 503, got %d", resp.StatusCode)
+			}
+			if got := atomic.LoadInt32(&attempts); got != tt.wantAttempts {
+				t.Errorf("want %d attempts, got %d", tt.wantAttempts, got)
+			}
+			if tt.maxElapsed > 0 && elapsed > tt.maxElapsed {
+				t.Errorf("want to stop within %v, took %v", tt.maxElapsed, elapsed)
+			}
+		})
+	}
+}
+
 func TestRetryNetworkError(t *testing.T) {
 	var attempts int32
 
@@ -323,6 +703,274 @@ func TestRetryNetworkError(t *testing.T) {
 	}
 }
 
+func TestRetryIdempotentOnly(t *testing.T) {
+	var attempts int32
+	var keys []string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		keys = append(keys, r.Header.Get("Idempotency-Key"))
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadURL := "http://" + ln.Addr().String()
+	ln.Close()
+
+	tests := map[string]struct {
+		req          *Request
+		key          bool
+		wantAttempts int32
+		wantRetries  int
+	}{
+		"POST is not retried on 5xx": {
+			req:          Post(srv.URL).BodyString("charge"),
+			wantAttempts: 1,
+		},
+		"GET is retried on 5xx": {
+			req:          Get(srv.URL),
+			wantAttempts: 3,
+			wantRetries:  2,
+		},
+		"POST with Idempotency-Key is retried": {
+			req:          Post(srv.URL).Header("Idempotency-Key", "k1"),
+			wantAttempts: 3,
+			wantRetries:  2,
+		},
+		"POST with generated key is retried": {
+			req:          Post(srv.URL).BodyString("charge"),
+			key:          true,
+			wantAttempts: 3,
+			wantRetries:  2,
+		},
+		"POST is retried when nothing was sent": {
+			req:         Post(deadURL).BodyString("charge"),
+			wantRetries: 2,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			atomic.StoreInt32(&attempts, 0)
+			keys = nil
+
+			var retries int
+			config := NewRetryConfig(3, ConstantBackoff(time.Millisecond), nil)
+			config.IdempotentOnly = true
+			config.IdempotencyKey = tt.key
+			config.OnRetry = func(int, *Response, time.Duration) { retries++ }
+
+			tt.req.DoWithRetry(context.Background(), config)
+
+			if got := atomic.LoadInt32(&attempts); got != tt.wantAttempts {
+				t.Errorf("want %d attempts, got %d", tt.wantAttempts, got)
+			}
+			if retries != tt.wantRetries {
+				t.Errorf("want %d retries, got %d", tt.wantRetries, retries)
+			}
+			if tt.key {
+				if keys[0] == "" || keys[0] != keys[len(keys)-1] {
+					t.Errorf("want the same generated key on every attempt, got %q", keys)
+				}
+			}
+		})
+	}
+}
+
+func TestRetryPerAttemptTimeout(t *testing.T) {
+	var attempts int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			select {
+			case <-r.Context().Done():
+			case <-time.After(time.Second):
+			}
+			return
+		}
+		w.Write([]byte("fast"))
+	}))
+	defer srv.Close()
+
+	config := &RetryConfig{
+		MaxAttempts:       3,
+		Delay:             time.Millisecond,
+		PerAttemptTimeout: 50 * time.Millisecond,
+		RetryIf:           defaultRetryIf,
+	}
+
+	t.Run("do with retry", func(t *testing.T) {
+		atomic.StoreInt32(&attempts, 0)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+
+		start := time.Now()
+		resp := Get(srv.URL).DoWithRetry(ctx, config)
+		if resp.Error() != nil {
+			t.Fatal(resp.Error())
+		}
+
+		if body, _ := resp.String(); body != "fast" {
+			t.Errorf("want body %q, got %q", "fast", body)
+		}
+		if got := atomic.LoadInt32(&attempts); got != 3 {
+			t.Errorf("want 3 attempts, got %d", got)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("want slow attempts cut short, took %v", elapsed)
+		}
+		for _, err := range resp.AttemptErrors() {
+			if !errors.Is(err, context.DeadlineExceeded) {
+				t.Errorf("want attempt deadline exceeded, got %v", err)
+			}
+		}
+		if ctx.Err() != nil {
+			t.Errorf("want parent context untouched, got %v", ctx.Err())
+		}
+	})
+
+	t.Run("transport", func(t *testing.T) {
+		atomic.StoreInt32(&attempts, 0)
+
+		client := &http.Client{Transport: RetryTransport(nil, config)}
+		resp, err := client.Get(srv.URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(body) != "fast" {
+			t.Errorf("want body %q, got %q", "fast", body)
+		}
+		if got := atomic.LoadInt32(&attempts); got != 3 {
+			t.Errorf("want 3 attempts, got %d", got)
+		}
+	})
+}
+
+func TestRetryBuilder(t *testing.T) {
+	var attempts int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1)%3 != 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	config := NewRetryConfig(3, ConstantBackoff(time.Millisecond), nil)
+
+	tests := map[string]*Request{
+		"builder":    Get(srv.URL).Retry(config),
+		"mirror":     Retry(config).URL(srv.URL),
+		"middleware": Use(RetryMiddleware(config)).URL(srv.URL),
+	}
+
+	for name, req := range tests {
+		t.Run(name, func(t *testing.T) {
+			atomic.StoreInt32(&attempts, 0)
+
+			resp := req.Do()
+			if resp.Error() != nil {
+				t.Fatal(resp.Error())
+			}
+
+			if body, _ := resp.String(); body != "ok" {
+				t.Errorf("want body ok, got %q", body)
+			}
+			if resp.Attempts() != 3 {
+				t.Errorf("want 3 attempts, got %d", resp.Attempts())
+			}
+		})
+	}
+
+	t.Run("without retry", func(t *testing.T) {
+		atomic.StoreInt32(&attempts, 0)
+
+		resp := Get(srv.URL).Do()
+		if resp.StatusCode != http.StatusServiceUnavailable || resp.Attempts() != 0 {
+			t.Errorf("want a single 503 attempt, got %d after %d attempts", resp.StatusCode, resp.Attempts())
+		}
+	})
+}
+
+func TestRetryOnValidationFailure(t *testing.T) {
+	var attempts int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&attempts, 1)
+		switch {
+		case r.URL.Path == "/down":
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case r.URL.Path == "/flaky" && n < 3:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		case r.URL.Path == "/eventual" && n < 3:
+			w.Write([]byte("[]"))
+		default:
+			w.Write([]byte("[1]"))
+		}
+	}))
+	defer srv.Close()
+
+	notEmpty := func(calls *int) Validator {
+		return func(resp *Response) error {
+			*calls++
+			if body, _ := resp.String(); body == "[]" {
+				return errors.New("empty list")
+			}
+			return resp.ExpectOK()
+		}
+	}
+
+	tests := map[string]struct {
+		path              string
+		onValidation      bool
+		wantAttempts      int32
+		wantValidatorRuns int
+		wantErr           bool
+	}{
+		"retries until valid":       {path: "/eventual", onValidation: true, wantAttempts: 3, wantValidatorRuns: 3},
+		"validation not retried":    {path: "/eventual", wantAttempts: 1, wantValidatorRuns: 1, wantErr: true},
+		"validated once after 503s": {path: "/flaky", wantAttempts: 3, wantValidatorRuns: 1},
+		"validated when exhausted":  {path: "/down", wantAttempts: 3, wantValidatorRuns: 1, wantErr: true},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			atomic.StoreInt32(&attempts, 0)
+
+			config := NewRetryConfig(3, ConstantBackoff(time.Millisecond), nil)
+			config.RetryOnValidationFailure = tt.onValidation
+
+			var calls int
+			resp := Get(srv.URL+tt.path).
+				Validate(notEmpty(&calls)).
+				DoWithRetry(context.Background(), config)
+
+			if got := atomic.LoadInt32(&attempts); got != tt.wantAttempts {
+				t.Errorf("want %d attempts, got %d", tt.wantAttempts, got)
+			}
+			if calls != tt.wantValidatorRuns {
+				t.Errorf("want validator to run %d times, got %d", tt.wantValidatorRuns, calls)
+			}
+			if gotErr := resp.Error() != nil; gotErr != tt.wantErr {
+				t.Errorf("want error %v, got %v", tt.wantErr, resp.Error())
+			}
+		})
+	}
+}
+
 func TestRetryNoRetryOnSuccess(t *testing.T) {
 	var attempts int32
 
@@ -349,3 +997,95 @@ func TestRetryNoRetryOnSuccess(t *testing.T) {
 		t.Errorf("want 1 attempt, got %d", attempts)
 	}
 }
+
+func TestRetryTransport(t *testing.T) {
+	var attempts int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "payload" {
+			t.Errorf("want body %q on every attempt, got %q", "payload", body)
+		}
+
+		if atomic.AddInt32(&attempts, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	config := NewRetryConfig(3, ConstantBackoff(5*time.Millisecond), nil)
+	client := &http.Client{Transport: RetryTransport(nil, config)}
+
+	t.Run("raw http.Client", func(t *testing.T) {
+		atomic.StoreInt32(&attempts, 0)
+
+		resp, err := client.Post(srv.URL, "text/plain", strings.NewReader("payload"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer resp.Body.Close()
+
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("want status 200, got %d", resp.StatusCode)
+		}
+		if got := atomic.LoadInt32(&attempts); got != 3 {
+			t.Errorf("want 3 attempts, got %d", got)
+		}
+	})
+
+	t.Run("rq client", func(t *testing.T) {
+		atomic.StoreInt32(&attempts, 0)
+
+		resp := Post(srv.URL).Client(client).BodyString("payload").Do()
+		if resp.Error() != nil {
+			t.Fatal(resp.Error())
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("want status 200, got %d", resp.StatusCode)
+		}
+	})
+
+	t.Run("gives up after max attempts", func(t *testing.T) {
+		var failures int32
+		failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			atomic.AddInt32(&failures, 1)
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer failing.Close()
+
+		resp := Get(failing.URL).Client(client).Do()
+		if resp.Error() != nil {
+			t.Fatal(resp.Error())
+		}
+		if resp.StatusCode != http.StatusBadGateway {
+			t.Errorf("want last status 502, got %d", resp.StatusCode)
+		}
+		if got := atomic.LoadInt32(&failures); got != 3 {
+			t.Errorf("want 3 attempts, got %d", got)
+		}
+	})
+}
+
+func TestRetryTransportContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	config := NewRetryConfig(10, ConstantBackoff(time.Second), nil)
+	client := &http.Client{Transport: RetryTransport(nil, config)}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	resp := Get(srv.URL).Client(client).Do(ctx)
+	if !errors.Is(resp.Error(), context.DeadlineExceeded) {
+		t.Errorf("want context.DeadlineExceeded, got %v", resp.Error())
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("want prompt return on context end, took %v", elapsed)
+	}
+}
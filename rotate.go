@@ -0,0 +1,128 @@
+package rq
+
+import (
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"sync"
+)
+
+// RotationStrategy selects how a ProxyRotator picks the next proxy
+type RotationStrategy int
+
+const (
+	// RotateRoundRobin cycles through the proxies in order
+	RotateRoundRobin RotationStrategy = iota
+	// RotateRandom picks a random proxy for every round trip
+	RotateRandom
+)
+
+// ProxyRotator is an http.RoundTripper that sends each round trip through
+// the next proxy of a pool. After a connection failure the next pick avoids
+// the proxy that failed, so a retry goes through a different proxy.
+// It is safe for concurrent use and can be shared between clients.
+type ProxyRotator struct {
+	transports []*http.Transport
+	strategy   RotationStrategy
+
+	mu         sync.Mutex
+	next       int
+	lastFailed int
+}
+
+// NewProxyRotator creates a ProxyRotator over configs. Each proxy gets its
+// own transport cloned from base, or from http.DefaultTransport if base is nil.
+func NewProxyRotator(base *http.Transport, configs []*ProxyConfig, strategy RotationStrategy) (*ProxyRotator, error) {
+	if len(configs) == 0 {
+		return nil, errors.New("no proxies to rotate")
+	}
+
+	transports := make([]*http.Transport, len(configs))
+	for i, config := range configs {
+		transport, err := config.CreateTransport(base)
+		if err != nil {
+			return nil, fmt.Errorf("proxy %d: %w", i, err)
+		}
+		transports[i] = transport
+	}
+
+	return &ProxyRotator{
+		transports: transports,
+		strategy:   strategy,
+		lastFailed: -1,
+	}, nil
+}
+
+// RoundTrip implements the RoundTripper interface
+func (p *ProxyRotator) RoundTrip(req *http.Request) (*http.Response, error) {
+	i := p.pick()
+
+	resp, err := p.transports[i].RoundTrip(req)
+	if err != nil {
+		p.mu.Lock()
+		p.lastFailed = i
+		p.mu.Unlock()
+	}
+
+	return resp, err
+}
+
+// CloseIdleConnections closes idle connections of every proxy transport
+func (p *ProxyRotator) CloseIdleConnections() {
+	for _, t := range p.transports {
+		t.CloseIdleConnections()
+	}
+}
+
+// pick returns the index of the transport for the next round trip
+func (p *ProxyRotator) pick() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	n := len(p.transports)
+	failed := p.lastFailed
+	p.lastFailed = -1
+
+	if p.strategy == RotateRandom {
+		i := rand.IntN(n)
+		if i == failed && n > 1 {
+			i = (i + 1 + rand.IntN(n-1)) % n
+		}
+		return i
+	}
+
+	i := p.next
+	if i == failed && n > 1 {
+		i = (i + 1) % n
+	}
+	p.next = (i + 1) % n
+	return i
+}
+
+// ProxyRotate creates a new request that rotates through a pool of proxies
+func ProxyRotate(configs []*ProxyConfig, strategy RotationStrategy) *Request {
+	return New().ProxyRotate(configs, strategy)
+}
+
+// ProxyRotate sends the request through a pool of proxies, picking one per
+// round trip, so every DoWithRetry attempt can use a different proxy.
+// The rotation state belongs to this request; share a ProxyRotator through
+// Client to rotate across requests.
+func (r *Request) ProxyRotate(configs []*ProxyConfig, strategy RotationStrategy) *Request {
+	if r.err != nil {
+		return r
+	}
+
+	client := r.cloneClient()
+
+	rotator, err := NewProxyRotator(getTransport(client), configs, strategy)
+	if err != nil {
+		r.err = fmt.Errorf("configure proxy rotation: %w", err)
+		return r
+	}
+
+	client.Transport = rotator
+	r.client = client
+	return r
+}
@@ -0,0 +1,83 @@
+package rq
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// newTestProxy starts an HTTP proxy that answers every request with its name
+func newTestProxy(t *testing.T, name string) *ProxyConfig {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(name))
+	}))
+	t.Cleanup(srv.Close)
+
+	config, err := ProxyFromURL(srv.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return config
+}
+
+func TestProxyRotateRoundRobin(t *testing.T) {
+	configs := []*ProxyConfig{newTestProxy(t, "a"), newTestProxy(t, "b"), newTestProxy(t, "c")}
+
+	rotator, err := NewProxyRotator(nil, configs, RotateRoundRobin)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := &http.Client{Transport: rotator}
+
+	var got []string
+	for i := 0; i < 4; i++ {
+		body, err := Get("http://origin.example.com").Client(client).Do().String()
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, body)
+	}
+
+	if want := "a,b,c,a"; strings.Join(got, ",") != want {
+		t.Errorf("want proxies %s, got %s", want, strings.Join(got, ","))
+	}
+}
+
+func TestProxyRotateRetryUsesOtherProxy(t *testing.T) {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	deadAddr := ln.Addr().String()
+	ln.Close()
+
+	host, port, _ := net.SplitHostPort(deadAddr)
+	dead := &ProxyConfig{Type: ProxyTypeHTTP, Host: host, Port: port}
+
+	for name, strategy := range map[string]RotationStrategy{
+		"round robin": RotateRoundRobin,
+		"random":      RotateRandom,
+	} {
+		t.Run(name, func(t *testing.T) {
+			for i := 0; i < 10; i++ {
+				config := NewRetryConfig(2, ConstantBackoff(time.Millisecond), nil)
+				resp := Get("http://origin.example.com").
+					ProxyRotate([]*ProxyConfig{dead, newTestProxy(t, "live")}, strategy).
+					DoWithRetry(context.Background(), config)
+				if resp.Error() != nil {
+					t.Fatalf("want retry through the live proxy, got %v", resp.Error())
+				}
+			}
+		})
+	}
+}
+
+func TestProxyRotateNoProxies(t *testing.T) {
+	if err := ProxyRotate(nil, RotateRoundRobin).err; err == nil {
+		t.Error("want error for empty proxy pool, got nil")
+	}
+}
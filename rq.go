@@ -1,45 +1,152 @@
 package rq
 
 import (
+	"bytes"
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"net/http/httptrace"
 	"net/url"
+	"slices"
+	"strings"
 	"time"
 )
 
 // Request represents an HTTP request configuration
 type Request struct {
-	client      *http.Client
-	method      string
-	url         string
-	headers     http.Header
-	queryParams url.Values
-	body        io.Reader
-	timeout     time.Duration
-	validators  []Validator
-	cookies     []*http.Cookie
-	err         error
+	client        *http.Client
+	method        string
+	url           string
+	headers       http.Header
+	defaults      http.Header
+	queryParams   url.Values
+	rawQuery      string
+	body          io.Reader
+	bodyBytes     []byte
+	bodyLength    int64
+	hasBodyLength bool
+	timeout       time.Duration
+	ctx           context.Context
+	validators    []Validator
+	cookies       []*http.Cookie
+	beforeSend    []func(*http.Request) error
+	afterResponse []func(*http.Request, *Response)
+	cache         CacheStore
+	teeResponse   io.Writer
+	maxBodySize   int64
+	retry         *RetryConfig
+
+	captureConnInfo bool
+	sseReconnect    bool
+	decompress      bool
+
+	// requestID is the ID generated by RequestIDMiddleware
+	requestID string
+
+	err error
 }
 
 // Response wraps http.Response with additional convenience methods
 type Response struct {
 	*http.Response
+
+	// RemoteAddr is the address the request connected to.
+	// It is only set when the request was made with CaptureConnInfo.
+	RemoteAddr string
+
+	// ConnReused reports whether the connection was reused from the pool.
+	// It is only set when the request was made with CaptureConnInfo.
+	ConnReused bool
+
 	body []byte
 	err  error
+
+	// stream is true when the body is left unread on the underlying response
+	stream bool
+
+	// cacheExpires is when a cached response stops being fresh
+	cacheExpires time.Time
+
+	timing RequestTiming
+
+	// receivedAt is when the response headers arrived
+	receivedAt time.Time
+
+	// unsent is true when a beforeSend hook stopped the request
+	unsent bool
+
+	// requestBody is the in-memory body that was sent, if known
+	requestBody []byte
+
+	// attempts and attemptErrors record the attempts made by DoWithRetry
+	attempts      int
+	attemptErrors []error
+
+	// requestID is the X-Request-ID that was sent, kept for failed requests
+	requestID string
 }
 
-// New creates a new HTTP request with default settings
+// New creates a new HTTP request with default settings, see SetDefaults
 func New() *Request {
+	d := currentDefaults()
 	return &Request{
-		client:      defaultClient,
-		method:      http.MethodGet,
-		headers:     make(http.Header),
-		queryParams: make(url.Values),
+		client:   d.client,
+		method:   http.MethodGet,
+		defaults: d.header,
 	}
 }
 
+// Clone returns a copy of the request that can be modified independently.
+// Headers, query parameters, cookies, validators and hooks are copied while
+// the *http.Client and cache store are shared. Bodies set with BodyString,
+// BodyBytes, BodyJSON or BodyForm get a fresh reader in each copy. A reader
+// set with Body is not cloned: it is shared and can only be consumed once.
+func (r *Request) Clone() *Request {
+	clone := *r
+	if r.bodyBytes != nil {
+		clone.body = bytes.NewReader(r.bodyBytes)
+	}
+	clone.headers = r.headers.Clone()
+	clone.queryParams = cloneValues(r.queryParams)
+	clone.validators = slices.Clone(r.validators)
+	clone.cookies = slices.Clone(r.cookies)
+	clone.beforeSend = slices.Clone(r.beforeSend)
+	clone.afterResponse = slices.Clone(r.afterResponse)
+	return &clone
+}
+
+// header returns the request headers, allocating them on first write so
+// requests without headers do not pay for the map
+func (r *Request) header() http.Header {
+	if r.headers == nil {
+		r.headers = make(http.Header)
+	}
+	return r.headers
+}
+
+// query returns the query parameters, allocating them on first write
+func (r *Request) query() url.Values {
+	if r.queryParams == nil {
+		r.queryParams = make(url.Values)
+	}
+	return r.queryParams
+}
+
+// cloneValues returns a deep copy of v
+func cloneValues(v url.Values) url.Values {
+	if v == nil {
+		return nil
+	}
+
+	clone := make(url.Values, len(v))
+	for k, vals := range v {
+		clone[k] = slices.Clone(vals)
+	}
+	return clone
+}
+
 // Get creates a new GET request
 func Get(urlStr string) *Request {
 	return New().Method(http.MethodGet).URL(urlStr)
@@ -117,7 +224,10 @@ func Timeout(timeout time.Duration) *Request {
 	return New().Timeout(timeout)
 }
 
-// Timeout sets the request timeout
+// Timeout sets the request timeout.
+// When the context passed to DoContext also carries a deadline, the earlier
+// of the two wins: the timeout is applied with context.WithTimeout on top of
+// the caller's context.
 func (r *Request) Timeout(timeout time.Duration) *Request {
 	if r.err != nil {
 		return r
@@ -126,6 +236,34 @@ func (r *Request) Timeout(timeout time.Duration) *Request {
 	return r
 }
 
+// Context creates a new request that is sent with ctx
+func Context(ctx context.Context) *Request {
+	return New().Context(ctx)
+}
+
+// Context sets the context used by Do and MustDo when none is passed.
+// A context passed explicitly to Do or DoContext takes precedence.
+// Timeout still applies on top of the stored context.
+func (r *Request) Context(ctx context.Context) *Request {
+	if r.err != nil {
+		return r
+	}
+	r.ctx = ctx
+	return r
+}
+
+// resolveContext returns ctx, falling back to the stored context and then
+// context.Background()
+func (r *Request) resolveContext(ctx context.Context) context.Context {
+	if ctx != nil {
+		return ctx
+	}
+	if r.ctx != nil {
+		return r.ctx
+	}
+	return context.Background()
+}
+
 // Header creates a new request with a header
 func Header(key, value string) *Request {
 	return New().Header(key, value)
@@ -136,7 +274,7 @@ func (r *Request) Header(key, value string) *Request {
 	if r.err != nil {
 		return r
 	}
-	r.headers.Add(key, value)
+	r.header().Add(key, value)
 	return r
 }
 
@@ -151,8 +289,50 @@ func (r *Request) Headers(headers map[string]string) *Request {
 		return r
 	}
 	for k, v := range headers {
-		r.headers.Set(k, v)
+		r.header().Set(k, v)
+	}
+	return r
+}
+
+// UserAgent creates a new request with a User-Agent header
+func UserAgent(ua string) *Request {
+	return New().UserAgent(ua)
+}
+
+// UserAgent sets the User-Agent header, replacing any default. An empty ua
+// sends no User-Agent at all instead of Go's default.
+func (r *Request) UserAgent(ua string) *Request {
+	if r.err != nil {
+		return r
 	}
+	// net/http omits the header when it is present but empty
+	r.header()["User-Agent"] = []string{ua}
+	return r
+}
+
+// Accept creates a new request with a weighted Accept header
+func Accept(contentTypes ...string) *Request {
+	return New().Accept(contentTypes...)
+}
+
+// Accept sets the Accept header to contentTypes in order of preference,
+// giving each a quality value 0.1 lower than the previous one, e.g.
+// "application/json, application/xml;q=0.9". Response.Decode then picks
+// the decoder for whichever type the server returned.
+func (r *Request) Accept(contentTypes ...string) *Request {
+	if r.err != nil || len(contentTypes) == 0 {
+		return r
+	}
+
+	parts := make([]string, len(contentTypes))
+	for i, contentType := range contentTypes {
+		parts[i] = contentType
+		if q := max(10-i, 1); q < 10 {
+			parts[i] += fmt.Sprintf(";q=0.%d", q)
+		}
+	}
+
+	r.header().Set("Accept", strings.Join(parts, ", "))
 	return r
 }
 
@@ -161,7 +341,9 @@ func Cookies(cookie ...*http.Cookie) *Request {
 	return New().Cookies(cookie...)
 }
 
-// Cookies adds a cookie
+// Cookies adds cookies to the request; repeated calls accumulate.
+// With a cookie jar, a cookie the jar already holds with the same value is
+// sent once.
 func (r *Request) Cookies(cookie ...*http.Cookie) *Request {
 	if r.err != nil {
 		return r
@@ -180,17 +362,30 @@ func (r *Request) QueryParam(key, value string) *Request {
 	if r.err != nil {
 		return r
 	}
-	r.queryParams.Add(key, value)
+	r.query().Add(key, value)
 	return r
 }
 
+// QueryParamIf adds a query parameter only if cond is true
+func (r *Request) QueryParamIf(cond bool, key, value string) *Request {
+	if !cond {
+		return r
+	}
+	return r.QueryParam(key, value)
+}
+
+// QueryParamNonEmpty adds a query parameter only if value is not empty
+func (r *Request) QueryParamNonEmpty(key, value string) *Request {
+	return r.QueryParamIf(value != "", key, value)
+}
+
 // QueryParams sets multiple query parameters
 func (r *Request) QueryParams(params map[string]string) *Request {
 	if r.err != nil {
 		return r
 	}
 	for k, v := range params {
-		r.queryParams.Set(k, v)
+		r.query().Set(k, v)
 	}
 	return r
 }
@@ -200,60 +395,254 @@ func QueryParams(params map[string]string) *Request {
 	return New().QueryParams(params)
 }
 
-// DoContext executes the request and returns a Response
+// QueryStruct creates a new request with query parameters encoded from a struct
+func QueryStruct(v any) *Request {
+	return New().QueryStruct(v)
+}
+
+// QueryStruct adds query parameters encoded from the fields of a struct.
+// Field names are taken from `url` tags; omitempty skips zero values and "-"
+// skips the field. Slices become repeated parameters and nil pointers are
+// skipped. Fields of embedded structs are promoted and nested structs are
+// flattened as parent[child]. time.Time is formatted as RFC 3339 unless the
+// unix option or a `layout` tag is given, and fields implementing
+// ValuesEncoder encode themselves. Values are added to any parameters
+// already set.
+func (r *Request) QueryStruct(v any) *Request {
+	if r.err != nil {
+		return r
+	}
+
+	values, err := structValues(v, "url")
+	if err != nil {
+		r.err = fmt.Errorf("failed to encode query: %w", err)
+		return r
+	}
+
+	for k, vs := range values {
+		for _, value := range vs {
+			r.query().Add(k, value)
+		}
+	}
+	return r
+}
+
+// RawQuery creates a new request with a pre-encoded query string
+func RawQuery(q string) *Request {
+	return New().RawQuery(q)
+}
+
+// RawQuery sets the query string exactly as given, without the sorting and
+// re-escaping of url.Values, e.g. for signed URLs. It replaces any query in
+// the URL, and sending fails if query parameters were also added.
+func (r *Request) RawQuery(q string) *Request {
+	if r.err != nil {
+		return r
+	}
+	r.rawQuery = strings.TrimPrefix(q, "?")
+	return r
+}
+
+// DoContext executes the request and returns a Response.
+// A nil ctx falls back to the context set with Context. Requests configured
+// with Retry go through DoWithRetry.
 func (r *Request) DoContext(ctx context.Context) *Response {
+	if r.retry != nil {
+		return r.DoWithRetry(ctx, r.retry)
+	}
+	return r.do(ctx, false)
+}
+
+// do sends the request. With stream set, the body is left unread on the
+// response for the caller to consume and close; caching and validators are
+// skipped since neither can work without the body.
+func (r *Request) do(ctx context.Context, stream bool) *Response {
 	if r.err != nil {
 		return &Response{err: r.err}
 	}
 
-	u, err := url.Parse(r.url)
+	ctx = r.resolveContext(ctx)
+
+	u, err := r.requestURL()
 	if err != nil {
-		return &Response{err: fmt.Errorf("invalid URL: %q: %w", r.url, err)}
+		return &Response{err: err}
 	}
 
-	if len(r.queryParams) > 0 {
-		u.RawQuery = r.queryParams.Encode()
+	if r.body != nil && (r.method == http.MethodGet || r.method == http.MethodHead) {
+		return &Response{err: fmt.Errorf("%s request must not have a body; use Post, Put or Patch", r.method)}
+	}
+
+	// A streamed body outlives this call, so the timeout is released when
+	// the body is closed instead
+	cancel := context.CancelFunc(func() {})
+	if r.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, r.timeout)
+	}
+	releaseOnReturn := true
+	defer func() {
+		if releaseOnReturn {
+			cancel()
+		}
+	}()
+
+	// Connection details share the timing trace so only one ClientTrace is
+	// attached; a trace already in ctx is composed with it
+	trace := newTimingTrace()
+	var connInfo *connInfoTrace
+	if r.captureConnInfo {
+		connInfo = &connInfoTrace{}
+		trace.GotConn = connInfo.gotConn
+	}
+	ctx = httptrace.WithClientTrace(ctx, &trace.ClientTrace)
+
+	// The URL is only re-encoded when the query changed
+	rawURL := r.url
+	if r.rawQuery != "" || len(r.queryParams) > 0 {
+		rawURL = u.String()
 	}
 
-	req, err := http.NewRequestWithContext(ctx, r.method, u.String(), r.body)
+	req, err := http.NewRequestWithContext(ctx, r.method, rawURL, r.body)
 	if err != nil {
 		return &Response{err: fmt.Errorf("failed to create request: %w", err)}
 	}
 
-	req.Header = r.headers.Clone()
+	if len(r.headers) > 0 || len(r.defaults) > 0 {
+		req.Header = r.requestHeader()
+	}
 
+	if r.hasBodyLength {
+		req.ContentLength = r.bodyLength
+		if r.bodyLength == 0 {
+			req.Body = http.NoBody
+		} else if r.bodyLength < 0 {
+			req.ContentLength = -1
+			req.GetBody = nil
+		}
+	}
+
+	// The client adds the jar's cookies itself; skip explicit cookies the
+	// jar would send anyway so they are not duplicated
+	var jarCookies []*http.Cookie
+	if r.client.Jar != nil && len(r.cookies) > 0 {
+		jarCookies = r.client.Jar.Cookies(req.URL)
+	}
 	for _, cookie := range r.cookies {
+		if slices.ContainsFunc(jarCookies, func(c *http.Cookie) bool {
+			return c.Name == cookie.Name && c.Value == cookie.Value
+		}) {
+			continue
+		}
 		req.AddCookie(cookie)
 	}
 
-	client := r.client
+	var cacheKey string
+	var cached *Response
+	if r.cache != nil && !stream && isCacheableMethod(r.method) && !hasCacheDirective(req.Header, "no-store") && req.Header.Get("Authorization") == "" {
+		cacheKey = r.method + " " + req.URL.String()
+		if c, ok := r.cache.Get(cacheKey); ok && c.varyMatches(req.Header) {
+			if c.isFresh() && !hasCacheDirective(req.Header, "no-cache") {
+				hit := c.cachedCopy()
+				hit.timing = RequestTiming{}
+				if err := r.applyTee(hit); err != nil {
+					hit.err = err
+					return hit
+				}
+				return r.runValidators(hit)
+			}
+			cached = c
+			if etag := c.Header.Get("ETag"); etag != "" {
+				req.Header.Set("If-None-Match", etag)
+			}
+		}
+	}
 
-	if r.timeout > 0 {
-		client = &http.Client{
-			Timeout:   r.timeout,
-			Transport: r.client.Transport,
+	for _, fn := range r.beforeSend {
+		if err := fn(req); err != nil {
+			return r.runAfterResponse(req, &Response{err: err, unsent: true})
 		}
 	}
 
-	resp, err := client.Do(req)
+	trace.start = time.Now()
+	resp, err := r.client.Do(req)
 	if err != nil {
-		return &Response{err: fmt.Errorf("request failed: %w", err)}
+		return r.runAfterResponse(req, &Response{err: fmt.Errorf("request failed: %w", err)})
+	}
+	receivedAt := time.Now()
+
+	if r.decompress {
+		decodeBody(resp)
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	if stream {
+		releaseOnReturn = false
+		resp.Body = &cancelOnClose{ReadCloser: limitBody(resp.Body, r.maxBodySize), cancel: cancel}
+
+		response := &Response{
+			Response:    resp,
+			stream:      true,
+			timing:      trace.timing(time.Now()),
+			receivedAt:  receivedAt,
+			requestBody: r.bodyBytes,
+		}
+
+		if connInfo != nil {
+			connInfo.apply(response)
+		}
+		if err := r.applyTee(response); err != nil {
+			response.err = err
+		}
+
+		return r.runAfterResponse(req, response)
+	}
+
+	body, err := readBody(resp.Body, r.maxBodySize)
 	_ = resp.Body.Close()
 	if err != nil {
-		return &Response{
-			Response: resp,
-			err:      fmt.Errorf("failed to read body: %w", err),
-		}
+		return r.runAfterResponse(req, &Response{
+			Response:   resp,
+			receivedAt: receivedAt,
+			err:        fmt.Errorf("failed to read body: %w", err),
+		})
 	}
 
 	response := &Response{
-		Response: resp,
-		body:     body,
+		Response:    resp,
+		body:        body,
+		timing:      trace.timing(time.Now()),
+		receivedAt:  receivedAt,
+		requestBody: r.bodyBytes,
+	}
+
+	if connInfo != nil {
+		connInfo.apply(response)
+	}
+
+	if err := r.applyTee(response); err != nil {
+		response.err = err
+		return r.runAfterResponse(req, response)
+	}
+
+	if cacheKey != "" {
+		response = r.updateCache(cacheKey, cached, response)
+	}
+
+	return r.runValidators(r.runAfterResponse(req, response))
+}
+
+// runAfterResponse passes the outcome of a request to the registered hooks
+func (r *Request) runAfterResponse(req *http.Request, response *Response) *Response {
+	response.requestID = req.Header.Get(RequestIDHeader)
+	if response.requestID == "" {
+		response.requestID = r.requestID
+	}
+	for _, fn := range r.afterResponse {
+		fn(req, response)
 	}
+	return response
+}
 
+// runValidators runs the request validators against the response, recording the first failure
+func (r *Request) runValidators(response *Response) *Response {
 	for _, validator := range r.validators {
 		if err := validator(response); err != nil {
 			response.err = fmt.Errorf("validation failed: %w", err)
@@ -264,36 +653,129 @@ func (r *Request) DoContext(ctx context.Context) *Response {
 	return response
 }
 
-// Do executes the request with background context and returns a Response
-func (r *Request) Do() *Response {
-	return r.DoContext(context.Background())
+// requestURL returns the URL the request is sent to, with query parameters applied
+func (r *Request) requestURL() (*url.URL, error) {
+	u, err := r.parseURL()
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case r.rawQuery != "" && len(r.queryParams) > 0:
+		return nil, errors.New("raw query cannot be combined with query parameters")
+	case r.rawQuery != "":
+		u.RawQuery = r.rawQuery
+	case len(r.queryParams) > 0:
+		u.RawQuery = r.queryParams.Encode()
+	}
+
+	return u, nil
+}
+
+// requestHeader returns the headers to send: the request's own headers plus
+// any default header from SetDefaults the request does not set itself
+func (r *Request) requestHeader() http.Header {
+	header := r.headers.Clone()
+	if header == nil {
+		header = make(http.Header, len(r.defaults))
+	}
+	for key, values := range r.defaults {
+		if _, ok := header[key]; !ok {
+			header[key] = slices.Clone(values)
+		}
+	}
+	return header
+}
+
+// parseURL parses the request URL and checks that it is absolute
+func (r *Request) parseURL() (*url.URL, error) {
+	if r.url == "" {
+		return nil, errors.New("URL is required")
+	}
+
+	u, err := url.Parse(r.url)
+	if err != nil {
+		return nil, fmt.Errorf("invalid URL: %q: %w", r.url, err)
+	}
+
+	if u.Scheme == "" {
+		return nil, fmt.Errorf("invalid URL: %q: missing scheme (e.g. \"https://\")", r.url)
+	}
+
+	if u.Host == "" {
+		return nil, fmt.Errorf("invalid URL: %q: missing host", r.url)
+	}
+
+	return u, nil
+}
+
+// Do executes the request and returns a Response.
+// An optional context may be passed; the context set with Context, or
+// context.Background(), is used otherwise.
+func (r *Request) Do(ctx ...context.Context) *Response {
+	return r.DoContext(optionalContext(ctx))
+}
+
+// Send is an alias of DoContext for callers looking for a Send method.
+// Do and DoContext are the canonical terminal methods.
+func (r *Request) Send(ctx context.Context) *Response {
+	return r.DoContext(ctx)
+}
+
+// optionalContext returns the first context or nil if none given
+func optionalContext(ctx []context.Context) context.Context {
+	if len(ctx) > 0 {
+		return ctx[0]
+	}
+	return nil
 }
 
 // MustDoContext executes the request with context and panics on error
+// with a *MustError naming the request.
 // This is useful for cases where you want to fail fast
 func (r *Request) MustDoContext(ctx context.Context) *Response {
 	resp := r.DoContext(ctx)
 	if resp.err != nil {
-		panic(resp.err)
+		method := r.method
+		if method == "" {
+			method = http.MethodGet
+		}
+		panic(&MustError{Method: method, URL: r.url, Err: resp.err})
 	}
 	return resp
 }
 
-// MustDo executes the request and panics on error
+// MustDo executes the request and panics on error.
+// Like Do, it accepts an optional context.
 // This is useful for cases where you want to fail fast
-func (r *Request) MustDo() *Response {
-	resp := r.DoContext(context.Background())
-	if resp.err != nil {
-		panic(resp.err)
-	}
-	return resp
+func (r *Request) MustDo(ctx ...context.Context) *Response {
+	return r.MustDoContext(optionalContext(ctx))
 }
 
-// Error returns any error that occurred
+// Error returns any error that occurred. Causes are wrapped with %w, so
+// errors.Is(resp.Error(), context.DeadlineExceeded) and errors.As with a
+// *net.OpError see through to them.
 func (r *Response) Error() error {
 	return r.err
 }
 
+// RequestID returns the X-Request-ID sent with the request, such as one
+// set by RequestIDMiddleware, or "" if there was none
+func (r *Response) RequestID() string {
+	return r.requestID
+}
+
+// Trailer returns the trailers sent after the response body, or nil if
+// there were none. Trailers only arrive once the body has been read to the
+// end, which Do and DoContext always do; for streamed bodies they are
+// available after reading them fully.
+func (r *Response) Trailer() http.Header {
+	if r.Response == nil {
+		return nil
+	}
+	return r.Response.Trailer
+}
+
 // IsOK returns true if status code is 2xx
 func (r *Response) IsOK() bool {
 	if r.err != nil || r.Response == nil {
@@ -3,6 +3,7 @@ package rq
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -24,6 +25,38 @@ func BenchmarkSimpleGET(b *testing.B) {
 	}
 }
 
+func BenchmarkDo(b *testing.B) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("OK"))
+	}))
+	defer srv.Close()
+
+	b.Run("get", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			if resp := Get(srv.URL).Do(); resp.Error() != nil {
+				b.Fatal(resp.Error())
+			}
+		}
+	})
+
+	b.Run("stdlib", func(b *testing.B) {
+		b.ReportAllocs()
+		client := &http.Client{}
+		for b.Loop() {
+			resp, err := client.Get(srv.URL)
+			if err != nil {
+				b.Fatal(err)
+			}
+			_, err = io.ReadAll(resp.Body)
+			resp.Body.Close()
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	})
+}
+
 // TODO: Need more benchmarks
 
 func BenchmarkComparison(b *testing.B) {
@@ -73,3 +106,22 @@ func BenchmarkComparison(b *testing.B) {
 	})
 
 }
+
+// benchRequest keeps built requests alive so they are not optimized away
+var benchRequest *Request
+
+func BenchmarkNew(b *testing.B) {
+	b.Run("bare", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			benchRequest = Get("http://example.com")
+		}
+	})
+
+	b.Run("with header", func(b *testing.B) {
+		b.ReportAllocs()
+		for b.Loop() {
+			benchRequest = Get("http://example.com").Header("Accept", "application/json")
+		}
+	})
+}
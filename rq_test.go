@@ -3,8 +3,13 @@ package rq
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"io"
+	"net"
 	"net/http"
 	"net/http/httptest"
+	"strings"
+	"sync"
 	"testing"
 	"time"
 )
@@ -118,6 +123,73 @@ func TestQueryParameters(t *testing.T) {
 	}
 }
 
+func TestRawQuery(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.URL.RawQuery))
+	}))
+	defer srv.Close()
+
+	tests := map[string]struct {
+		req     *Request
+		want    string
+		wantErr bool
+	}{
+		"kept verbatim": {
+			req:  Get(srv.URL).RawQuery("z=1&a=hello%20world&sig=AbC%2Bd"),
+			want: "z=1&a=hello%20world&sig=AbC%2Bd",
+		},
+		"replaces url query": {
+			req:  RawQuery("?b=2&a=1").URL(srv.URL + "?old=1"),
+			want: "b=2&a=1",
+		},
+		"combined with params": {
+			req:     Get(srv.URL).RawQuery("a=1").QueryParam("b", "2"),
+			wantErr: true,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := tt.req.Do()
+			if tt.wantErr {
+				if resp.Error() == nil {
+					t.Error("want error, got nil")
+				}
+				return
+			}
+			if resp.Error() != nil {
+				t.Fatal(resp.Error())
+			}
+
+			if body, _ := resp.String(); body != tt.want {
+				t.Errorf("want query %q, got %q", tt.want, body)
+			}
+		})
+	}
+}
+
+func TestConditionalQueryParameters(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.URL.RawQuery))
+	}))
+	defer srv.Close()
+
+	resp := Get(srv.URL).
+		QueryParamIf(true, "a", "1").
+		QueryParamIf(false, "b", "2").
+		QueryParamNonEmpty("c", "3").
+		QueryParamNonEmpty("d", "").
+		Do()
+
+	body, err := resp.String()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body != "a=1&c=3" {
+		t.Errorf("want query %q, got %q", "a=1&c=3", body)
+	}
+}
+
 func TestHeaders(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		headers := make(map[string]string)
@@ -242,6 +314,37 @@ func TestCookies(t *testing.T) {
 		}
 	})
 
+	t.Run("with cookie jar", func(t *testing.T) {
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/login" {
+				http.SetCookie(w, &http.Cookie{Name: "session", Value: "abc123"})
+				return
+			}
+			if len(r.Cookies()) != 2 {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer ts.Close()
+
+		base := EnableCookies()
+		if err := base.Clone().URL(ts.URL + "/login").Do().Error(); err != nil {
+			t.Fatal(err)
+		}
+
+		resp := base.Clone().URL(ts.URL).
+			Cookies(&http.Cookie{Name: "session", Value: "abc123"}).
+			Cookies(&http.Cookie{Name: "lang", Value: "en"}).
+			Do()
+		if resp.Error() != nil {
+			t.Fatal(resp.Error())
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("want status 200, got %d", resp.StatusCode)
+		}
+	})
+
 	t.Run("no cookies", func(t *testing.T) {
 		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 			if len(r.Cookies()) != 0 {
@@ -324,6 +427,127 @@ func TestTimeout(t *testing.T) {
 	}
 }
 
+func TestTimeoutKeepsClient(t *testing.T) {
+	r := EnableCookies().FollowRedirects(false)
+	client := r.client
+
+	r.Timeout(time.Second)
+	if r.client != client {
+		t.Error("want Timeout to keep the configured client")
+	}
+	if r.client.Jar == nil || r.client.CheckRedirect == nil {
+		t.Error("want cookie jar and redirect policy to be kept")
+	}
+}
+
+func TestTimeoutAndContextDeadline(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(2 * time.Second):
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	tests := map[string]struct {
+		ctxTimeout time.Duration
+		reqTimeout time.Duration
+	}{
+		"context deadline shorter": {
+			ctxTimeout: 100 * time.Millisecond,
+			reqTimeout: time.Second,
+		},
+		"request timeout shorter": {
+			ctxTimeout: time.Second,
+			reqTimeout: 100 * time.Millisecond,
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), tt.ctxTimeout)
+			defer cancel()
+
+			start := time.Now()
+			resp := Get(srv.URL).Timeout(tt.reqTimeout).DoContext(ctx)
+			elapsed := time.Since(start)
+
+			if !errors.Is(resp.Error(), context.DeadlineExceeded) {
+				t.Fatalf("want context.DeadlineExceeded, got %v", resp.Error())
+			}
+			if elapsed >= 500*time.Millisecond {
+				t.Errorf("want the 100ms deadline to win, took %v", elapsed)
+			}
+		})
+	}
+}
+
+func TestResponseTrailer(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Trailer", "Grpc-Status, Grpc-Message")
+		w.Write([]byte("payload"))
+		w.Header().Set("Grpc-Status", "0")
+		w.Header().Set("Grpc-Message", "ok")
+	}))
+	defer srv.Close()
+
+	resp := Get(srv.URL).Do()
+	if resp.Error() != nil {
+		t.Fatal(resp.Error())
+	}
+
+	if got := resp.Trailer().Get("Grpc-Status"); got != "0" {
+		t.Errorf("want Grpc-Status trailer 0, got %q", got)
+	}
+	if got := resp.Trailer().Get("Grpc-Message"); got != "ok" {
+		t.Errorf("want Grpc-Message trailer ok, got %q", got)
+	}
+
+	if got := New().Do().Trailer(); got != nil {
+		t.Errorf("want nil trailer for failed request, got %v", got)
+	}
+}
+
+func TestResponseErrorWrapping(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(100 * time.Millisecond)
+	}))
+	defer srv.Close()
+
+	t.Run("deadline", func(t *testing.T) {
+		resp := Get(srv.URL).Timeout(10 * time.Millisecond).Do()
+		if !errors.Is(resp.Error(), context.DeadlineExceeded) {
+			t.Errorf("want context.DeadlineExceeded, got %v", resp.Error())
+		}
+	})
+
+	t.Run("canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		resp := Get(srv.URL).Do(ctx)
+		if !errors.Is(resp.Error(), context.Canceled) {
+			t.Errorf("want context.Canceled, got %v", resp.Error())
+		}
+	})
+
+	t.Run("network error", func(t *testing.T) {
+		resp := Get("http://127.0.0.1:1").Do()
+
+		var opErr *net.OpError
+		if !errors.As(resp.Error(), &opErr) {
+			t.Errorf("want *net.OpError, got %T: %v", resp.Error(), resp.Error())
+		}
+	})
+
+	t.Run("success", func(t *testing.T) {
+		if err := Get(srv.URL).Do().Error(); err != nil {
+			t.Errorf("want nil, got %v", err)
+		}
+	})
+}
+
 func TestErrorHandling(t *testing.T) {
 	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		switch r.URL.Path {
@@ -358,12 +582,30 @@ func TestErrorHandling(t *testing.T) {
 		t.Errorf("want ExpectStatus(404) to return nil, got %v", err)
 	}
 
-	// TODO: implement AsHTTPError
-	// resp = Get(srv.URL+"/500").Do(ctx)
-	// httpErr := resp.AsHTTPError()
-	// if httpErr == nil {
-	// 	t.Errorf("want AsHTTPError to return error for 500")
-	// }
+	resp = Get(srv.URL + "/500").Do()
+	httpErr := resp.AsHTTPError()
+	if httpErr == nil {
+		t.Fatal("want AsHTTPError to return error for 500")
+	}
+
+	var he *HTTPError
+	if !errors.As(httpErr, &he) {
+		t.Fatalf("want *HTTPError, got %T", httpErr)
+	}
+	if he.StatusCode != http.StatusInternalServerError {
+		t.Errorf("want status 500, got %d", he.StatusCode)
+	}
+	if string(he.Body) != "Internal Server Error" {
+		t.Errorf("want body %q, got %q", "Internal Server Error", he.Body)
+	}
+	if he.URL != srv.URL+"/500" {
+		t.Errorf("want URL %q, got %q", srv.URL+"/500", he.URL)
+	}
+
+	resp = Get(srv.URL).Do()
+	if err := resp.AsHTTPError(); err != nil {
+		t.Errorf("want AsHTTPError to return nil for 200, got %v", err)
+	}
 }
 
 func TestMustDoContext(t *testing.T) {
@@ -384,8 +626,23 @@ func TestMustDoContext(t *testing.T) {
 
 	t.Run("panics on context timeout", func(t *testing.T) {
 		defer func() {
-			if r := recover(); r == nil {
-				t.Error("MustDoContext should have panicked on timeout")
+			r := recover()
+			if r == nil {
+				t.Fatal("MustDoContext should have panicked on timeout")
+			}
+
+			mustErr, ok := r.(*MustError)
+			if !ok {
+				t.Fatalf("want *MustError panic value, got %T", r)
+			}
+			if mustErr.Method != http.MethodGet || mustErr.URL != srv.URL {
+				t.Errorf("want GET %s, got %s %s", srv.URL, mustErr.Method, mustErr.URL)
+			}
+			if !errors.Is(mustErr, context.DeadlineExceeded) {
+				t.Errorf("want context.DeadlineExceeded, got %v", mustErr)
+			}
+			if !strings.HasPrefix(mustErr.Error(), "GET "+srv.URL+": ") {
+				t.Errorf("want message naming the request, got %q", mustErr.Error())
 			}
 		}()
 
@@ -437,3 +694,243 @@ func TestMustDo(t *testing.T) {
 		Get("invalid-url").MustDo()
 	})
 }
+
+func TestDoOptionalContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(50 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	t.Run("without context", func(t *testing.T) {
+		resp := Get(srv.URL).Do()
+		if resp.Error() != nil {
+			t.Fatal(resp.Error())
+		}
+	})
+
+	t.Run("with context", func(t *testing.T) {
+		ctx := context.Background()
+		resp := Get(srv.URL).Do(ctx)
+		if resp.Error() != nil {
+			t.Fatal(resp.Error())
+		}
+	})
+
+	t.Run("context is honored", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		resp := Get(srv.URL).Do(ctx)
+		if !errors.Is(resp.Error(), context.DeadlineExceeded) {
+			t.Errorf("want context.DeadlineExceeded, got %v", resp.Error())
+		}
+	})
+
+	t.Run("MustDo with context panics on timeout", func(t *testing.T) {
+		defer func() {
+			if r := recover(); r == nil {
+				t.Error("MustDo should have panicked on timeout")
+			}
+		}()
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+
+		Get(srv.URL).MustDo(ctx)
+	})
+}
+
+func TestSend(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("sent"))
+	}))
+	defer srv.Close()
+
+	body, err := Get(srv.URL).Send(context.Background()).String()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if body != "sent" {
+		t.Errorf("want body %q, got %q", "sent", body)
+	}
+}
+
+func TestStoredContext(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(time.Second):
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	t.Run("used by Do", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancel()
+
+		resp := Get(srv.URL).Context(ctx).Do()
+		if !errors.Is(resp.Error(), context.DeadlineExceeded) {
+			t.Errorf("want context.DeadlineExceeded, got %v", resp.Error())
+		}
+	})
+
+	t.Run("explicit context wins", func(t *testing.T) {
+		stored, cancel := context.WithCancel(context.Background())
+		cancel()
+
+		explicit, cancelExplicit := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		defer cancelExplicit()
+
+		resp := Get(srv.URL).Context(stored).DoContext(explicit)
+		if !errors.Is(resp.Error(), context.DeadlineExceeded) {
+			t.Errorf("want context.DeadlineExceeded from explicit context, got %v", resp.Error())
+		}
+	})
+
+	t.Run("composes with Timeout", func(t *testing.T) {
+		start := time.Now()
+		resp := Get(srv.URL).Context(context.Background()).Timeout(20 * time.Millisecond).Do()
+		if !errors.Is(resp.Error(), context.DeadlineExceeded) {
+			t.Errorf("want context.DeadlineExceeded, got %v", resp.Error())
+		}
+		if elapsed := time.Since(start); elapsed >= 500*time.Millisecond {
+			t.Errorf("want timeout to apply, took %v", elapsed)
+		}
+	})
+}
+
+func TestRequiredFields(t *testing.T) {
+	tests := map[string]struct {
+		req     *Request
+		wantErr string
+	}{
+		"empty URL": {
+			req:     New(),
+			wantErr: "URL is required",
+		},
+		"missing scheme": {
+			req:     Get("example.com/path"),
+			wantErr: "missing scheme",
+		},
+		"missing host": {
+			req:     Get("http://"),
+			wantErr: "missing host",
+		},
+		"GET with body": {
+			req:     Get("http://example.com").BodyString("data"),
+			wantErr: "GET request must not have a body",
+		},
+		"HEAD with body": {
+			req:     Head("http://example.com").BodyString("data"),
+			wantErr: "HEAD request must not have a body",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := tt.req.Do()
+			if resp.Error() == nil {
+				t.Fatalf("want error containing %q, got nil", tt.wantErr)
+			}
+			if !strings.Contains(resp.Error().Error(), tt.wantErr) {
+				t.Errorf("want error containing %q, got %q", tt.wantErr, resp.Error())
+			}
+		})
+	}
+}
+
+func TestClone(t *testing.T) {
+	base := New().
+		Header("X-Base", "1").
+		QueryParam("q", "base").
+		Timeout(time.Second).
+		Validate(Validate.OK())
+
+	clone := base.Clone().
+		Header("X-Clone", "1").
+		QueryParam("q", "clone").
+		Validate(Validate.StatusCode(http.StatusOK))
+
+	if base.headers.Get("X-Clone") != "" {
+		t.Error("want clone headers not to leak into base")
+	}
+	if got := base.queryParams["q"]; len(got) != 1 {
+		t.Errorf("want base query to be unchanged, got %v", got)
+	}
+	if len(base.validators) != 1 {
+		t.Errorf("want base validators to be unchanged, got %d", len(base.validators))
+	}
+
+	if clone.headers.Get("X-Base") != "1" {
+		t.Error("want clone to keep base headers")
+	}
+	if clone.timeout != time.Second {
+		t.Errorf("want clone timeout 1s, got %v", clone.timeout)
+	}
+	if clone.client != base.client {
+		t.Error("want clone to share the client")
+	}
+}
+
+func TestCloneBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		io.Copy(w, r.Body)
+	}))
+	defer srv.Close()
+
+	base := Post(srv.URL).BodyString("payload")
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			body, err := base.Clone().Header("X-Clone", "1").Do().String()
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			if body != "payload" {
+				t.Errorf("want body %q, got %q", "payload", body)
+			}
+		}()
+	}
+	wg.Wait()
+
+	body, _ := base.Do().String()
+	if body != "payload" {
+		t.Errorf("want base body %q, got %q", "payload", body)
+	}
+}
+
+func TestQueryStruct(t *testing.T) {
+	type params struct {
+		Query string   `url:"q"`
+		Tags  []string `url:"tag"`
+		Page  int      `url:"page,omitempty"`
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.URL.RawQuery))
+	}))
+	defer srv.Close()
+
+	body, err := Get(srv.URL).
+		QueryParam("tag", "first").
+		QueryStruct(params{Query: "go", Tags: []string{"a", "b"}}).
+		Do().
+		String()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := "q=go&tag=first&tag=a&tag=b"; body != want {
+		t.Errorf("want query %q, got %q", want, body)
+	}
+
+	if err := QueryStruct(42).err; err == nil {
+		t.Error("want error for non-struct, got nil")
+	}
+}
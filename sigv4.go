@@ -0,0 +1,244 @@
+package rq
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"path"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	sigV4Algorithm  = "AWS4-HMAC-SHA256"
+	sigV4TimeFormat = "20060102T150405Z"
+	sigV4DateFormat = "20060102"
+)
+
+// AWSV4 is an AuthProvider that signs requests with AWS Signature Version 4.
+// Signing depends on the final headers, query and body, so it runs right
+// before the request is sent rather than when the provider is applied.
+type AWSV4 struct {
+	AccessKey    string
+	SecretKey    string
+	SessionToken string
+	Region       string
+	Service      string
+
+	// now is used in tests to fix the signing time
+	now func() time.Time
+}
+
+// Apply registers the signer to run when the request is sent
+func (a *AWSV4) Apply(r *Request) *Request {
+	if r.err != nil {
+		return r
+	}
+	r.beforeSend = append(r.beforeSend, a.sign)
+	return r
+}
+
+// sign adds the x-amz-* headers and the Authorization header to req
+func (a *AWSV4) sign(req *http.Request) error {
+	now := time.Now
+	if a.now != nil {
+		now = a.now
+	}
+	t := now().UTC()
+
+	payload, err := bufferRequestBody(req)
+	if err != nil {
+		return fmt.Errorf("sigv4: %w", err)
+	}
+
+	payloadHash := sha256Hex(payload)
+	amzDate := t.Format(sigV4TimeFormat)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if a.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", a.SessionToken)
+	}
+
+	canonicalHeaders, signedHeaders := sigV4CanonicalHeaders(req)
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		sigV4CanonicalURI(req.URL, a.Service),
+		sigV4CanonicalQuery(req.URL),
+		canonicalHeaders,
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := strings.Join([]string{t.Format(sigV4DateFormat), a.Region, a.Service, "aws4_request"}, "/")
+
+	stringToSign := strings.Join([]string{
+		sigV4Algorithm,
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	key := sigV4SigningKey(a.SecretKey, t.Format(sigV4DateFormat), a.Region, a.Service)
+	signature := hex.EncodeToString(hmacSHA256(key, []byte(stringToSign)))
+
+	req.Header.Set("Authorization", fmt.Sprintf(
+		"%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		sigV4Algorithm, a.AccessKey, scope, signedHeaders, signature,
+	))
+
+	return nil
+}
+
+// bufferRequestBody reads the request body into memory and restores it
+func bufferRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return nil, nil
+	}
+
+	data, err := io.ReadAll(req.Body)
+	_ = req.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("read request body: %w", err)
+	}
+
+	req.Body = io.NopCloser(bytes.NewReader(data))
+	req.GetBody = func() (io.ReadCloser, error) {
+		return io.NopCloser(bytes.NewReader(data)), nil
+	}
+	req.ContentLength = int64(len(data))
+
+	return data, nil
+}
+
+// sigV4CanonicalURI returns the canonical path, defaulting to "/". Each
+// segment is encoded leaving only unreserved characters as they are. Every
+// service but S3 normalizes the path and encodes the segments twice.
+func sigV4CanonicalURI(u *url.URL, service string) string {
+	p := u.EscapedPath()
+	if p == "" {
+		return "/"
+	}
+
+	s3 := service == "s3"
+	if !s3 {
+		cleaned := path.Clean(p)
+		if strings.HasSuffix(p, "/") && cleaned != "/" {
+			cleaned += "/"
+		}
+		p = cleaned
+	}
+
+	segments := strings.Split(p, "/")
+	for i, segment := range segments {
+		if decoded, err := url.PathUnescape(segment); err == nil {
+			segment = decoded
+		}
+		segment = sigV4Escape(segment)
+		if !s3 {
+			segment = sigV4Escape(segment)
+		}
+		segments[i] = segment
+	}
+
+	return strings.Join(segments, "/")
+}
+
+// sigV4CanonicalQuery returns the query sorted by key and value with RFC 3986 escaping
+func sigV4CanonicalQuery(u *url.URL) string {
+	query := u.Query()
+	keys := make([]string, 0, len(query))
+	for k := range query {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var pairs []string
+	for _, k := range keys {
+		values := append([]string(nil), query[k]...)
+		sort.Strings(values)
+		for _, v := range values {
+			pairs = append(pairs, sigV4Escape(k)+"="+sigV4Escape(v))
+		}
+	}
+
+	return strings.Join(pairs, "&")
+}
+
+// sigV4CanonicalHeaders returns the canonical header block and the signed header list.
+// Host, Content-Type and all X-Amz-* headers are signed.
+func sigV4CanonicalHeaders(req *http.Request) (string, string) {
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+
+	headers := map[string]string{"host": host}
+	for k, v := range req.Header {
+		lk := strings.ToLower(k)
+		if lk == "content-type" || strings.HasPrefix(lk, "x-amz-") {
+			trimmed := make([]string, len(v))
+			for i, s := range v {
+				trimmed[i] = strings.Join(strings.Fields(s), " ")
+			}
+			headers[lk] = strings.Join(trimmed, ",")
+		}
+	}
+
+	names := make([]string, 0, len(headers))
+	for k := range headers {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, k := range names {
+		b.WriteString(k)
+		b.WriteByte(':')
+		b.WriteString(headers[k])
+		b.WriteByte('\n')
+	}
+
+	return b.String(), strings.Join(names, ";")
+}
+
+// sigV4SigningKey derives the signing key for the given date, region and service
+func sigV4SigningKey(secret, date, region, service string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secret), []byte(date))
+	key = hmacSHA256(key, []byte(region))
+	key = hmacSHA256(key, []byte(service))
+	return hmacSHA256(key, []byte("aws4_request"))
+}
+
+// sigV4Escape escapes everything except RFC 3986 unreserved characters
+func sigV4Escape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') || (c >= '0' && c <= '9') ||
+			c == '-' || c == '_' || c == '.' || c == '~' {
+			b.WriteByte(c)
+		} else {
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
+
+func hmacSHA256(key, data []byte) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,166 @@
+package rq
+
+import (
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSigV4SigningKey(t *testing.T) {
+	// Example from the AWS Signature Version 4 documentation
+	key := sigV4SigningKey("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "20120215", "us-east-1", "iam")
+
+	want := "f4780e2d9f65fa895f9c67b32ce1baf0b0d8a43505a000a1a9e090d414db404d"
+	if got := hex.EncodeToString(key); got != want {
+		t.Errorf("want signing key %s, got %s", want, got)
+	}
+}
+
+func TestAWSV4Sign(t *testing.T) {
+	signer := &AWSV4{
+		AccessKey: "AKIDEXAMPLE",
+		SecretKey: "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY",
+		Region:    "us-east-1",
+		Service:   "service",
+		now: func() time.Time {
+			return time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC)
+		},
+	}
+
+	req, err := http.NewRequest(http.MethodPost, "https://example.amazonaws.com/path?b=2%20c&a=1", strings.NewReader(`{"k":"v"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	if err := signer.sign(req); err != nil {
+		t.Fatal(err)
+	}
+
+	wantHash := "666c1aa02e8068c6d5cc1d3295009432c16790bec28ec8ce119d0d1a18d61319"
+	if got := req.Header.Get("X-Amz-Content-Sha256"); got != wantHash {
+		t.Errorf("want payload hash %s, got %s", wantHash, got)
+	}
+	if got := req.Header.Get("X-Amz-Date"); got != "20150830T123600Z" {
+		t.Errorf("want X-Amz-Date 20150830T123600Z, got %s", got)
+	}
+
+	wantAuth := "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/service/aws4_request, " +
+		"SignedHeaders=content-type;host;x-amz-content-sha256;x-amz-date, " +
+		"Signature=7db5b0a450fa59522ad2c1c90e4f443c548a8df3cace939099170dda3b74d539"
+	if got := req.Header.Get("Authorization"); got != wantAuth {
+		t.Errorf("want Authorization\n%s\ngot\n%s", wantAuth, got)
+	}
+
+	body, err := io.ReadAll(req.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(body) != `{"k":"v"}` {
+		t.Errorf("want body to be preserved, got %q", body)
+	}
+}
+
+func TestSigV4CanonicalURI(t *testing.T) {
+	tests := map[string]struct {
+		rawURL  string
+		service string
+		want    string
+	}{
+		// Vectors from the AWS SigV4 test suite, whose canonical requests
+		// encode the path once as S3 does
+		"suite get-space": {
+			rawURL:  "https://example.amazonaws.com/example space/",
+			service: "s3",
+			want:    "/example%20space/",
+		},
+		"suite get-slashes": {
+			rawURL:  "https://example.amazonaws.com//example//",
+			service: "service",
+			want:    "/example/",
+		},
+		"suite get-relative-relative": {
+			rawURL:  "https://example.amazonaws.com/example1/example2/../..",
+			service: "service",
+			want:    "/",
+		},
+		"double encoded": {
+			rawURL:  "https://example.amazonaws.com/documents and settings/",
+			service: "execute-api",
+			want:    "/documents%2520and%2520settings/",
+		},
+		"reserved characters": {
+			rawURL:  "https://example.amazonaws.com/a:b=c!d$e",
+			service: "lambda",
+			want:    "/a%253Ab%253Dc%2521d%2524e",
+		},
+		"s3 keeps path": {
+			rawURL:  "https://bucket.s3.amazonaws.com//key/../a:b",
+			service: "s3",
+			want:    "//key/../a%3Ab",
+		},
+		"empty path": {
+			rawURL:  "https://example.amazonaws.com",
+			service: "service",
+			want:    "/",
+		},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			u, err := url.Parse(tc.rawURL)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got := sigV4CanonicalURI(u, tc.service); got != tc.want {
+				t.Errorf("want %s, got %s", tc.want, got)
+			}
+		})
+	}
+}
+
+func TestAWSV4Provider(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasPrefix(r.Header.Get("Authorization"), "AWS4-HMAC-SHA256 Credential=AKID/") {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.Header.Get("X-Amz-Security-Token") != "session" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		if string(body) != "payload" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer srv.Close()
+
+	provider := &AWSV4{
+		AccessKey:    "AKID",
+		SecretKey:    "secret",
+		SessionToken: "session",
+		Region:       "eu-west-1",
+		Service:      "execute-api",
+	}
+
+	resp := Post(srv.URL).
+		Body(io.NopCloser(strings.NewReader("payload"))).
+		WithAuth(provider).
+		Do()
+	if resp.Error() != nil {
+		t.Fatal(resp.Error())
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("want status 200, got %d", resp.StatusCode)
+	}
+}
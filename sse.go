@@ -0,0 +1,172 @@
+package rq
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// defaultSSERetry is the reconnection delay used until the server sends a retry field
+const defaultSSERetry = 3 * time.Second
+
+// Event is a single Server-Sent Event
+type Event struct {
+	// ID is the last event ID seen on the stream
+	ID string
+	// Event is the event type, "message" unless the server set one
+	Event string
+	// Data is the event payload, with multiple data lines joined by "\n"
+	Data string
+	// Retry is the reconnection delay requested by the server, if any
+	Retry time.Duration
+}
+
+// SSEReconnect creates a new request that reconnects to an event stream
+func SSEReconnect() *Request {
+	return New().SSEReconnect()
+}
+
+// SSEReconnect makes SSE reconnect when the server closes the stream or the
+// connection drops. The Last-Event-ID header is sent on reconnect and the
+// delay between attempts follows the server's retry field, defaulting to 3s.
+func (r *Request) SSEReconnect() *Request {
+	if r.err != nil {
+		return r
+	}
+	r.sseReconnect = true
+	return r
+}
+
+// SSE consumes a text/event-stream response, calling handler for every event
+// as it arrives. The body is streamed and never buffered as a whole.
+// SSE returns nil when the server closes the stream, or the context error
+// once ctx is done. Validators are not run on event streams. The client
+// timeout does not apply; use Timeout or ctx to bound the stream.
+func (r *Request) SSE(ctx context.Context, handler func(Event)) error {
+	if r.err != nil {
+		return r.err
+	}
+
+	ctx = r.resolveContext(ctx)
+	stream := r.withoutClientTimeout()
+
+	var lastID string
+	var retry time.Duration
+
+	for {
+		req := stream.Clone().Header("Accept", "text/event-stream").Header("Cache-Control", "no-cache")
+		if lastID != "" {
+			req.Header("Last-Event-ID", lastID)
+		}
+
+		err := req.readEvents(ctx, &lastID, &retry, handler)
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+
+		var statusErr *HTTPError
+		if !r.sseReconnect || errors.As(err, &statusErr) {
+			return err
+		}
+
+		delay := retry
+		if delay == 0 {
+			delay = defaultSSERetry
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(delay):
+		}
+	}
+}
+
+// readEvents opens a single event stream and dispatches events until it ends
+func (r *Request) readEvents(ctx context.Context, lastID *string, retry *time.Duration, handler func(Event)) error {
+	resp := r.do(ctx, true)
+	if resp.err != nil {
+		return resp.err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return &HTTPError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			URL:        resp.Request.URL.String(),
+		}
+	}
+
+	return parseEvents(resp.Body, lastID, retry, handler)
+}
+
+// parseEvents reads the text/event-stream format from body.
+// An event is dispatched on every blank line; a trailing incomplete event is dropped.
+func parseEvents(body io.Reader, lastID *string, retry *time.Duration, handler func(Event)) error {
+	reader := bufio.NewReader(body)
+
+	var eventType string
+	var data strings.Builder
+	var hasData bool
+
+	for {
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				return nil
+			}
+			return fmt.Errorf("read event stream: %w", err)
+		}
+		line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r")
+
+		if line == "" {
+			if hasData {
+				event := Event{
+					ID:    *lastID,
+					Event: eventType,
+					Data:  strings.TrimSuffix(data.String(), "\n"),
+					Retry: *retry,
+				}
+				if event.Event == "" {
+					event.Event = "message"
+				}
+				handler(event)
+			}
+
+			eventType = ""
+			data.Reset()
+			hasData = false
+			continue
+		}
+
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+
+		field, value, _ := strings.Cut(line, ":")
+		value = strings.TrimPrefix(value, " ")
+
+		switch field {
+		case "event":
+			eventType = value
+		case "data":
+			data.WriteString(value)
+			data.WriteByte('\n')
+			hasData = true
+		case "id":
+			if !strings.ContainsRune(value, 0) {
+				*lastID = value
+			}
+		case "retry":
+			if ms, err := strconv.Atoi(value); err == nil && ms >= 0 {
+				*retry = time.Duration(ms) * time.Millisecond
+			}
+		}
+	}
+}
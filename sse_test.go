@@ -0,0 +1,174 @@
+package rq
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestParseEvents(t *testing.T) {
+	stream := ": comment\n" +
+		"data: first\n\n" +
+		"event: update\r\n" +
+		"id: 42\r\n" +
+		"data: line one\r\n" +
+		"data: line two\r\n\r\n" +
+		"retry: 1500\n" +
+		"data:no space\n\n" +
+		"data: incomplete"
+
+	var events []Event
+	var lastID string
+	var retry time.Duration
+
+	err := parseEvents(strings.NewReader(stream), &lastID, &retry, func(e Event) {
+		events = append(events, e)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []Event{
+		{Event: "message", Data: "first"},
+		{ID: "42", Event: "update", Data: "line one\nline two"},
+		{ID: "42", Event: "message", Data: "no space", Retry: 1500 * time.Millisecond},
+	}
+
+	if len(events) != len(want) {
+		t.Fatalf("want %d events, got %d: %+v", len(want), len(events), events)
+	}
+	for i := range want {
+		if events[i] != want[i] {
+			t.Errorf("event %d: want %+v, got %+v", i, want[i], events[i])
+		}
+	}
+}
+
+func TestSSE(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Accept") != "text/event-stream" {
+			t.Errorf("want Accept text/event-stream, got %q", r.Header.Get("Accept"))
+		}
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i := 1; i <= 3; i++ {
+			fmt.Fprintf(w, "id: %d\ndata: event %d\n\n", i, i)
+			w.(http.Flusher).Flush()
+		}
+	}))
+	defer srv.Close()
+
+	var got []string
+	err := Get(srv.URL).SSE(context.Background(), func(e Event) {
+		got = append(got, e.Data)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if want := []string{"event 1", "event 2", "event 3"}; strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("want %v, got %v", want, got)
+	}
+}
+
+func TestSSEContextCancel(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		fmt.Fprint(w, "data: hello\n\n")
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+
+	err := Get(srv.URL).SSE(ctx, func(e Event) {
+		cancel()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("want context.Canceled, got %v", err)
+	}
+}
+
+func TestSSEStatusError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	}))
+	defer srv.Close()
+
+	err := Get(srv.URL).SSEReconnect().SSE(context.Background(), func(e Event) {})
+
+	var httpErr *HTTPError
+	if !errors.As(err, &httpErr) || httpErr.StatusCode != http.StatusUnauthorized {
+		t.Errorf("want HTTPError with status 401, got %v", err)
+	}
+}
+
+func TestSSEReconnect(t *testing.T) {
+	var connections int32
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(&connections, 1)
+		w.Header().Set("Content-Type", "text/event-stream")
+
+		if n == 1 {
+			fmt.Fprint(w, "retry: 10\nid: 1\ndata: first\n\n")
+			return
+		}
+
+		if got := r.Header.Get("Last-Event-ID"); got != "1" {
+			t.Errorf("want Last-Event-ID 1, got %q", got)
+		}
+		fmt.Fprint(w, "id: 2\ndata: second\n\n")
+	}))
+	defer srv.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+
+	var got []string
+	err := Get(srv.URL).SSEReconnect().SSE(ctx, func(e Event) {
+		got = append(got, e.Data)
+		if len(got) == 2 {
+			cancel()
+		}
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("want context.Canceled, got %v", err)
+	}
+
+	if strings.Join(got, ",") != "first,second" {
+		t.Errorf("want [first second], got %v", got)
+	}
+}
+
+func TestSSEOutlivesClientTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/event-stream")
+		for i := 1; i <= 2; i++ {
+			fmt.Fprintf(w, "data: event %d\n\n", i)
+			w.(http.Flusher).Flush()
+			time.Sleep(100 * time.Millisecond)
+		}
+	}))
+	defer srv.Close()
+
+	var got []string
+	err := Get(srv.URL).
+		ClientOptions(WithTimeout(50*time.Millisecond)).
+		SSE(context.Background(), func(e Event) {
+			got = append(got, e.Data)
+		})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(got) != 2 {
+		t.Errorf("want 2 events, got %v", got)
+	}
+}
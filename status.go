@@ -0,0 +1,85 @@
+package rq
+
+import "net/http"
+
+// StatusClass is the class of an HTTP status code, as given by its first digit
+type StatusClass int
+
+const (
+	StatusUnknown StatusClass = iota
+	StatusInformational
+	StatusSuccess
+	StatusRedirection
+	StatusClientError
+	StatusServerError
+)
+
+// String returns the name of the status class
+func (c StatusClass) String() string {
+	switch c {
+	case StatusInformational:
+		return "Informational"
+	case StatusSuccess:
+		return "Success"
+	case StatusRedirection:
+		return "Redirection"
+	case StatusClientError:
+		return "ClientError"
+	case StatusServerError:
+		return "ServerError"
+	default:
+		return "Unknown"
+	}
+}
+
+// StatusText returns the standard text for the status code, or an empty
+// string if there is no response
+func (r *Response) StatusText() string {
+	if r.Response == nil {
+		return ""
+	}
+	return http.StatusText(r.StatusCode)
+}
+
+// StatusClass returns the class of the status code. It returns StatusUnknown
+// if there is no response or the code is outside 100-599.
+func (r *Response) StatusClass() StatusClass {
+	if r.Response == nil {
+		return StatusUnknown
+	}
+
+	switch r.StatusCode / 100 {
+	case 1:
+		return StatusInformational
+	case 2:
+		return StatusSuccess
+	case 3:
+		return StatusRedirection
+	case 4:
+		return StatusClientError
+	case 5:
+		return StatusServerError
+	default:
+		return StatusUnknown
+	}
+}
+
+// IsInformational returns true if status code is 1xx
+func (r *Response) IsInformational() bool {
+	return r.err == nil && r.StatusClass() == StatusInformational
+}
+
+// IsRedirect returns true if status code is 3xx
+func (r *Response) IsRedirect() bool {
+	return r.err == nil && r.StatusClass() == StatusRedirection
+}
+
+// IsClientError returns true if status code is 4xx
+func (r *Response) IsClientError() bool {
+	return r.err == nil && r.StatusClass() == StatusClientError
+}
+
+// IsServerError returns true if status code is 5xx
+func (r *Response) IsServerError() bool {
+	return r.err == nil && r.StatusClass() == StatusServerError
+}
@@ -0,0 +1,100 @@
+package rq
+
+import (
+	"errors"
+	"net/http"
+	"testing"
+)
+
+func TestStatusClass(t *testing.T) {
+	tests := map[string]struct {
+		resp      *Response
+		wantClass StatusClass
+		wantText  string
+	}{
+		"nil response": {
+			resp:      &Response{},
+			wantClass: StatusUnknown,
+			wantText:  "",
+		},
+		"informational": {
+			resp:      &Response{Response: &http.Response{StatusCode: http.StatusContinue}},
+			wantClass: StatusInformational,
+			wantText:  "Continue",
+		},
+		"success": {
+			resp:      &Response{Response: &http.Response{StatusCode: http.StatusCreated}},
+			wantClass: StatusSuccess,
+			wantText:  "Created",
+		},
+		"redirection": {
+			resp:      &Response{Response: &http.Response{StatusCode: http.StatusFound}},
+			wantClass: StatusRedirection,
+			wantText:  "Found",
+		},
+		"client error": {
+			resp:      &Response{Response: &http.Response{StatusCode: http.StatusNotFound}},
+			wantClass: StatusClientError,
+			wantText:  "Not Found",
+		},
+		"server error": {
+			resp:      &Response{Response: &http.Response{StatusCode: http.StatusBadGateway}},
+			wantClass: StatusServerError,
+			wantText:  "Bad Gateway",
+		},
+		"out of range": {
+			resp:      &Response{Response: &http.Response{StatusCode: 999}},
+			wantClass: StatusUnknown,
+			wantText:  "",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tt.resp.StatusClass(); got != tt.wantClass {
+				t.Errorf("want class %v, got %v", tt.wantClass, got)
+			}
+			if got := tt.resp.StatusText(); got != tt.wantText {
+				t.Errorf("want text %q, got %q", tt.wantText, got)
+			}
+		})
+	}
+}
+
+func TestStatusPredicates(t *testing.T) {
+	withStatus := func(code int) *Response {
+		return &Response{Response: &http.Response{StatusCode: code}}
+	}
+
+	tests := map[string]struct {
+		resp                                          *Response
+		informational, redirect, clientErr, serverErr bool
+	}{
+		"informational": {resp: withStatus(http.StatusSwitchingProtocols), informational: true},
+		"success":       {resp: withStatus(http.StatusOK)},
+		"redirect":      {resp: withStatus(http.StatusMovedPermanently), redirect: true},
+		"client error":  {resp: withStatus(http.StatusTeapot), clientErr: true},
+		"server error":  {resp: withStatus(http.StatusServiceUnavailable), serverErr: true},
+		"nil response":  {resp: &Response{}},
+		"failed request": {
+			resp: &Response{Response: &http.Response{StatusCode: http.StatusInternalServerError}, err: errors.New("validation failed")},
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			if got := tt.resp.IsInformational(); got != tt.informational {
+				t.Errorf("want IsInformational %v, got %v", tt.informational, got)
+			}
+			if got := tt.resp.IsRedirect(); got != tt.redirect {
+				t.Errorf("want IsRedirect %v, got %v", tt.redirect, got)
+			}
+			if got := tt.resp.IsClientError(); got != tt.clientErr {
+				t.Errorf("want IsClientError %v, got %v", tt.clientErr, got)
+			}
+			if got := tt.resp.IsServerError(); got != tt.serverErr {
+				t.Errorf("want IsServerError %v, got %v", tt.serverErr, got)
+			}
+		})
+	}
+}
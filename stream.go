@@ -0,0 +1,44 @@
+package rq
+
+import (
+	"context"
+	"io"
+	"net/http"
+)
+
+// cancelOnClose releases the request context once a streamed body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+	return err
+}
+
+// StreamResponse is a response whose body has not been read.
+// The caller must close Body.
+type StreamResponse struct {
+	StatusCode int
+	Header     http.Header
+	Body       io.ReadCloser
+}
+
+// DoStream sends the request and returns the response without reading the
+// body, leaving memory use up to the caller. Validators are not run since
+// they would need the body, and DoStream does not retry. Timeout and
+// MaxBodySize still apply while the body is read; the client timeout does not.
+func (r *Request) DoStream(ctx context.Context) (*StreamResponse, error) {
+	resp := r.withoutClientTimeout().do(ctx, true)
+	if resp.err != nil {
+		return nil, resp.err
+	}
+
+	return &StreamResponse{
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		Body:       resp.Body,
+	}, nil
+}
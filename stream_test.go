@@ -0,0 +1,120 @@
+package rq
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDoStream(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Test", "stream")
+		w.WriteHeader(http.StatusAccepted)
+		w.Write([]byte(strings.Repeat("x", 1<<20)))
+	}))
+	defer srv.Close()
+
+	var tee bytes.Buffer
+	resp, err := Get(srv.URL).
+		Validate(Validate.BodyContains("never checked")).
+		TeeResponse(&tee).
+		DoStream(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusAccepted {
+		t.Errorf("want status 202, got %d", resp.StatusCode)
+	}
+	if got := resp.Header.Get("X-Test"); got != "stream" {
+		t.Errorf("want X-Test header %q, got %q", "stream", got)
+	}
+
+	n, err := io.Copy(io.Discard, resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != 1<<20 {
+		t.Errorf("want %d bytes, got %d", 1<<20, n)
+	}
+	if tee.Len() != 1<<20 {
+		t.Errorf("want %d bytes teed, got %d", 1<<20, tee.Len())
+	}
+}
+
+func TestDoStreamTimeoutAppliesToBody(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("start"))
+		w.(http.Flusher).Flush()
+		<-r.Context().Done()
+	}))
+	defer srv.Close()
+
+	resp, err := Get(srv.URL).Timeout(50 * time.Millisecond).DoStream(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	if _, err := io.ReadAll(resp.Body); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("want context.DeadlineExceeded, got %v", err)
+	}
+}
+
+func TestDoStreamOutlivesClientTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("start"))
+		w.(http.Flusher).Flush()
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte(" end"))
+	}))
+	defer srv.Close()
+
+	resp, err := Get(srv.URL).ClientOptions(WithTimeout(50 * time.Millisecond)).DoStream(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "start end" {
+		t.Errorf("want start end, got %q", data)
+	}
+}
+
+func TestDoStreamMaxBodySize(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(strings.Repeat("x", 100)))
+	}))
+	defer srv.Close()
+
+	resp, err := Get(srv.URL).MaxBodySize(10).DoStream(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+
+	data, err := io.ReadAll(resp.Body)
+	if !errors.Is(err, ErrBodyTooLarge) {
+		t.Errorf("want ErrBodyTooLarge, got %v", err)
+	}
+	if len(data) != 10 {
+		t.Errorf("want 10 bytes before the limit, got %d", len(data))
+	}
+}
+
+func TestDoStreamError(t *testing.T) {
+	if _, err := New().DoStream(context.Background()); err == nil {
+		t.Error("want error for missing URL, got nil")
+	}
+}
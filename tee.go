@@ -0,0 +1,48 @@
+package rq
+
+import (
+	"fmt"
+	"io"
+)
+
+// TeeResponse creates a new request that copies the response body to w
+func TeeResponse(w io.Writer) *Request {
+	return New().TeeResponse(w)
+}
+
+// TeeResponse copies the response body to w. Buffered bodies are written to w
+// once they have been read; streamed bodies are copied to w as the caller
+// reads them. No extra copy of the body is kept.
+func (r *Request) TeeResponse(w io.Writer) *Request {
+	if r.err != nil {
+		return r
+	}
+	r.teeResponse = w
+	return r
+}
+
+// applyTee hands the response body to the tee writer, if any
+func (r *Request) applyTee(resp *Response) error {
+	if r.teeResponse == nil {
+		return nil
+	}
+
+	if resp.stream {
+		resp.Response.Body = &teeReadCloser{
+			Reader: io.TeeReader(resp.Response.Body, r.teeResponse),
+			Closer: resp.Response.Body,
+		}
+		return nil
+	}
+
+	if _, err := r.teeResponse.Write(resp.body); err != nil {
+		return fmt.Errorf("failed to tee response body: %w", err)
+	}
+	return nil
+}
+
+// teeReadCloser reads through a TeeReader and closes the original body
+type teeReadCloser struct {
+	io.Reader
+	io.Closer
+}
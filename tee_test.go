@@ -0,0 +1,77 @@
+package rq
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestTeeResponse(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("response body"))
+	}))
+	defer srv.Close()
+
+	var buf bytes.Buffer
+	resp := Get(srv.URL).TeeResponse(&buf).Do()
+	if resp.Error() != nil {
+		t.Fatal(resp.Error())
+	}
+
+	if got := buf.String(); got != "response body" {
+		t.Errorf("want tee %q, got %q", "response body", got)
+	}
+
+	body, _ := resp.String()
+	if body != "response body" {
+		t.Errorf("want body %q, got %q", "response body", body)
+	}
+}
+
+func TestTeeResponseStream(t *testing.T) {
+	var buf bytes.Buffer
+	resp := &Response{
+		Response: &http.Response{Body: io.NopCloser(strings.NewReader("streamed body"))},
+		stream:   true,
+	}
+
+	if err := New().TeeResponse(&buf).applyTee(resp); err != nil {
+		t.Fatal(err)
+	}
+	if buf.Len() != 0 {
+		t.Errorf("want nothing written before the body is read, got %q", buf.String())
+	}
+
+	data, err := io.ReadAll(resp.bodyReader())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "streamed body" {
+		t.Errorf("want body %q, got %q", "streamed body", data)
+	}
+	if got := buf.String(); got != "streamed body" {
+		t.Errorf("want tee %q, got %q", "streamed body", got)
+	}
+}
+
+type failingWriter struct{}
+
+func (failingWriter) Write(p []byte) (int, error) {
+	return 0, errors.New("disk full")
+}
+
+func TestTeeResponseWriteError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	resp := Get(srv.URL).TeeResponse(failingWriter{}).Do()
+	if resp.Error() == nil || !strings.Contains(resp.Error().Error(), "disk full") {
+		t.Errorf("want tee write error, got %v", resp.Error())
+	}
+}
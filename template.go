@@ -0,0 +1,35 @@
+package rq
+
+import (
+	"fmt"
+	"sync"
+)
+
+var (
+	templatesMu sync.RWMutex
+	templates   = make(map[string]*Request)
+)
+
+// RegisterTemplate stores a copy of r under name for later use with FromTemplate.
+// Registering an existing name replaces the previous template.
+func RegisterTemplate(name string, r *Request) {
+	templatesMu.Lock()
+	defer templatesMu.Unlock()
+	templates[name] = r.Clone()
+}
+
+// FromTemplate returns a clone of the named template.
+// If no template is registered under name, the returned request carries an error.
+func FromTemplate(name string) *Request {
+	templatesMu.RLock()
+	tmpl, ok := templates[name]
+	templatesMu.RUnlock()
+
+	if !ok {
+		r := New()
+		r.err = fmt.Errorf("unknown request template %q", name)
+		return r
+	}
+
+	return tmpl.Clone()
+}
@@ -0,0 +1,47 @@
+package rq
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestTemplates(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.Header.Get("Authorization") + " " + r.URL.Path))
+	}))
+	defer srv.Close()
+
+	base := Get(srv.URL + "/users").BearerToken("token")
+	RegisterTemplate("api", base)
+
+	// Changes after registration do not affect the template
+	base.BearerToken("changed")
+
+	var wg sync.WaitGroup
+	for range 5 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			resp := FromTemplate("api").Header("X-Extra", "1").Do()
+			if resp.Error() != nil {
+				t.Error(resp.Error())
+				return
+			}
+
+			body, _ := resp.String()
+			if body != "Bearer token /users" {
+				t.Errorf("want %q, got %q", "Bearer token /users", body)
+			}
+		}()
+	}
+	wg.Wait()
+
+	resp := FromTemplate("missing").Do()
+	if resp.Error() == nil || !strings.Contains(resp.Error().Error(), `unknown request template "missing"`) {
+		t.Errorf("want unknown template error, got %v", resp.Error())
+	}
+}
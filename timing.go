@@ -0,0 +1,88 @@
+package rq
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+// RequestTiming holds the duration of each phase of a request.
+// Phases the transport did not report, for example DNS and connect on a
+// reused connection or with a custom transport, are left as zero.
+type RequestTiming struct {
+	// Start is when the request was sent
+	Start time.Time
+
+	DNSLookup       time.Duration
+	Connect         time.Duration
+	TLSHandshake    time.Duration
+	TimeToFirstByte time.Duration
+	Total           time.Duration
+}
+
+// Timing returns the timing of the request that produced the response
+func (r *Response) Timing() RequestTiming {
+	return r.timing
+}
+
+// timingTrace records httptrace events for a single request. The
+// ClientTrace is embedded so the hooks and the state they record into
+// are allocated together.
+type timingTrace struct {
+	httptrace.ClientTrace
+
+	mu sync.Mutex
+
+	start     time.Time
+	dnsStart  time.Time
+	dnsDone   time.Time
+	connStart time.Time
+	connDone  time.Time
+	tlsStart  time.Time
+	tlsDone   time.Time
+	firstByte time.Time
+}
+
+// newTimingTrace returns a timingTrace whose hooks record into itself
+func newTimingTrace() *timingTrace {
+	t := &timingTrace{}
+	t.DNSStart = func(httptrace.DNSStartInfo) { t.record(&t.dnsStart) }
+	t.DNSDone = func(httptrace.DNSDoneInfo) { t.record(&t.dnsDone) }
+	t.ConnectStart = func(string, string) { t.record(&t.connStart) }
+	t.ConnectDone = func(string, string, error) { t.record(&t.connDone) }
+	t.TLSHandshakeStart = func() { t.record(&t.tlsStart) }
+	t.TLSHandshakeDone = func(tls.ConnectionState, error) { t.record(&t.tlsDone) }
+	t.GotFirstResponseByte = func() { t.record(&t.firstByte) }
+	return t
+}
+
+// record sets field to the current time
+func (t *timingTrace) record(field *time.Time) {
+	t.mu.Lock()
+	*field = time.Now()
+	t.mu.Unlock()
+}
+
+// timing computes the phase durations, with Total measured up to end
+func (t *timingTrace) timing(end time.Time) RequestTiming {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return RequestTiming{
+		Start:           t.start,
+		DNSLookup:       between(t.dnsStart, t.dnsDone),
+		Connect:         between(t.connStart, t.connDone),
+		TLSHandshake:    between(t.tlsStart, t.tlsDone),
+		TimeToFirstByte: between(t.start, t.firstByte),
+		Total:           between(t.start, end),
+	}
+}
+
+// between returns end - start, or zero if either is unset
+func between(start, end time.Time) time.Duration {
+	if start.IsZero() || end.IsZero() {
+		return 0
+	}
+	return end.Sub(start)
+}
@@ -0,0 +1,72 @@
+package rq
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestResponseTiming(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(20 * time.Millisecond)
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	resp := Get(srv.URL).Client(&http.Client{Transport: &http.Transport{}}).Do()
+	if resp.Error() != nil {
+		t.Fatal(resp.Error())
+	}
+
+	timing := resp.Timing()
+	if timing.Total < 20*time.Millisecond {
+		t.Errorf("want total >= 20ms, got %v", timing.Total)
+	}
+	if timing.TimeToFirstByte < 20*time.Millisecond || timing.TimeToFirstByte > timing.Total {
+		t.Errorf("want time to first byte between 20ms and total, got %v", timing.TimeToFirstByte)
+	}
+	if timing.Connect <= 0 {
+		t.Errorf("want connect time on a new connection, got %v", timing.Connect)
+	}
+	if timing.TLSHandshake != 0 {
+		t.Errorf("want no TLS handshake for plain HTTP, got %v", timing.TLSHandshake)
+	}
+}
+
+func TestResponseTimingTLS(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	resp := Get(srv.URL).Client(srv.Client()).Do()
+	if resp.Error() != nil {
+		t.Fatal(resp.Error())
+	}
+
+	if resp.Timing().TLSHandshake <= 0 {
+		t.Errorf("want TLS handshake time, got %v", resp.Timing().TLSHandshake)
+	}
+}
+
+func TestResponseTimingCustomTransport(t *testing.T) {
+	transport := RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		rec := httptest.NewRecorder()
+		rec.WriteString("ok")
+		return rec.Result(), nil
+	})
+
+	resp := Get("http://example.com").Client(&http.Client{Transport: transport}).Do()
+	if resp.Error() != nil {
+		t.Fatal(resp.Error())
+	}
+
+	timing := resp.Timing()
+	if timing.DNSLookup != 0 || timing.Connect != 0 || timing.TLSHandshake != 0 || timing.TimeToFirstByte != 0 {
+		t.Errorf("want zero phase timings for custom transport, got %+v", timing)
+	}
+	if timing.Total <= 0 {
+		t.Errorf("want total to be measured, got %v", timing.Total)
+	}
+}
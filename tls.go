@@ -0,0 +1,107 @@
+package rq
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"slices"
+)
+
+// TLSConfig creates a new request with a TLS configuration
+func TLSConfig(config *tls.Config) *Request {
+	return New().TLSConfig(config)
+}
+
+// TLSConfig sets the TLS configuration on a copy of the client transport.
+// Other transport settings, such as a proxy, are preserved. A nil config
+// resets TLS to the defaults.
+func (r *Request) TLSConfig(config *tls.Config) *Request {
+	return r.updateTLS(func(c *tls.Config) {
+		if config == nil {
+			*c = tls.Config{} // #nosec G402 -- same as a transport without TLSClientConfig
+			return
+		}
+		*c = *config.Clone()
+	})
+}
+
+// InsecureSkipVerify creates a new request that may skip certificate verification
+func InsecureSkipVerify(skip bool) *Request {
+	return New().InsecureSkipVerify(skip)
+}
+
+// InsecureSkipVerify disables certificate verification when skip is true.
+// This makes the connection vulnerable to interception and should only be used for testing.
+func (r *Request) InsecureSkipVerify(skip bool) *Request {
+	return r.updateTLS(func(c *tls.Config) {
+		c.InsecureSkipVerify = skip // #nosec G402 -- opt-in for testing
+	})
+}
+
+// RootCAs creates a new request that trusts the given certificate pool
+func RootCAs(pool *x509.CertPool) *Request {
+	return New().RootCAs(pool)
+}
+
+// RootCAs sets the certificate authorities used to verify the server
+func (r *Request) RootCAs(pool *x509.CertPool) *Request {
+	return r.updateTLS(func(c *tls.Config) {
+		c.RootCAs = pool
+	})
+}
+
+// MinTLSVersion creates a new request with a minimum TLS version
+func MinTLSVersion(version uint16) *Request {
+	return New().MinTLSVersion(version)
+}
+
+// MinTLSVersion sets the minimum TLS version, such as tls.VersionTLS12
+func (r *Request) MinTLSVersion(version uint16) *Request {
+	return r.updateTLS(func(c *tls.Config) {
+		c.MinVersion = version
+	})
+}
+
+// ClientCert creates a new request that presents a client certificate
+func ClientCert(cert tls.Certificate) *Request {
+	return New().ClientCert(cert)
+}
+
+// ClientCert adds a client certificate for mutual TLS
+func (r *Request) ClientCert(cert tls.Certificate) *Request {
+	return r.updateTLS(func(c *tls.Config) {
+		c.Certificates = append(slices.Clone(c.Certificates), cert)
+	})
+}
+
+// ClientCertFiles creates a new request that presents a client certificate loaded from disk
+func ClientCertFiles(certPath, keyPath string) *Request {
+	return New().ClientCertFiles(certPath, keyPath)
+}
+
+// ClientCertFiles loads a PEM encoded certificate and key pair and adds it as a client certificate
+func (r *Request) ClientCertFiles(certPath, keyPath string) *Request {
+	if r.err != nil {
+		return r
+	}
+
+	cert, err := tls.LoadX509KeyPair(certPath, keyPath)
+	if err != nil {
+		r.err = fmt.Errorf("load client certificate: %w", err)
+		return r
+	}
+
+	return r.ClientCert(cert)
+}
+
+// updateTLS applies fn to the TLS config of a cloned client and transport
+func (r *Request) updateTLS(fn func(*tls.Config)) *Request {
+	return r.updateTransport("configure TLS", func(t *http.Transport) {
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{} // #nosec G402 -- MinVersion is left to the caller
+		}
+		fn(t.TLSClientConfig)
+		stripH2(t)
+	})
+}
@@ -0,0 +1,262 @@
+package rq
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestTLSOptions(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("secure"))
+	}))
+	defer srv.Close()
+
+	pool := x509.NewCertPool()
+	pool.AddCert(srv.Certificate())
+
+	t.Run("default client rejects self-signed cert", func(t *testing.T) {
+		resp := Get(srv.URL).Do()
+		if resp.Error() == nil {
+			t.Error("want certificate error, got nil")
+		}
+	})
+
+	t.Run("InsecureSkipVerify", func(t *testing.T) {
+		resp := Get(srv.URL).InsecureSkipVerify(true).Do()
+		if resp.Error() != nil {
+			t.Fatal(resp.Error())
+		}
+	})
+
+	t.Run("RootCAs", func(t *testing.T) {
+		resp := Get(srv.URL).RootCAs(pool).Do()
+		if resp.Error() != nil {
+			t.Fatal(resp.Error())
+		}
+	})
+
+	t.Run("TLSConfig", func(t *testing.T) {
+		resp := Get(srv.URL).TLSConfig(&tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}).Do()
+		if resp.Error() != nil {
+			t.Fatal(resp.Error())
+		}
+	})
+
+	t.Run("nil TLSConfig resets to defaults", func(t *testing.T) {
+		r := Get(srv.URL).InsecureSkipVerify(true).TLSConfig(nil)
+		if r.err != nil {
+			t.Fatal(r.err)
+		}
+		if r.client.Transport.(*http.Transport).TLSClientConfig.InsecureSkipVerify {
+			t.Error("want InsecureSkipVerify to be reset")
+		}
+
+		resp := r.Do()
+		if resp.Error() == nil {
+			t.Error("want certificate error, got nil")
+		}
+	})
+
+	t.Run("MinTLSVersion", func(t *testing.T) {
+		r := Get(srv.URL).RootCAs(pool).MinTLSVersion(tls.VersionTLS13)
+		if got := r.client.Transport.(*http.Transport).TLSClientConfig.MinVersion; got != tls.VersionTLS13 {
+			t.Errorf("want min version TLS 1.3, got %x", got)
+		}
+
+		resp := r.Do()
+		if resp.Error() != nil {
+			t.Fatal(resp.Error())
+		}
+	})
+}
+
+func TestTLSPreservesTransport(t *testing.T) {
+	proxyURL, _ := url.Parse("http://proxy.example.com:8080")
+	base := &http.Transport{
+		Proxy:        http.ProxyURL(proxyURL),
+		MaxIdleConns: 7,
+	}
+	client := &http.Client{Transport: base}
+
+	r := New().Client(client).InsecureSkipVerify(true)
+	if r.err != nil {
+		t.Fatal(r.err)
+	}
+
+	transport := r.client.Transport.(*http.Transport)
+	if transport.Proxy == nil {
+		t.Error("want proxy to be preserved")
+	}
+	if transport.MaxIdleConns != 7 {
+		t.Errorf("want MaxIdleConns 7, got %d", transport.MaxIdleConns)
+	}
+	if !transport.TLSClientConfig.InsecureSkipVerify {
+		t.Error("want InsecureSkipVerify to be set")
+	}
+	if base.TLSClientConfig != nil && base.TLSClientConfig.InsecureSkipVerify {
+		t.Error("want original transport to be untouched")
+	}
+
+	r = New().ProxyURL("socks5://proxy.example.com:1080").RootCAs(x509.NewCertPool())
+	if r.err != nil {
+		t.Fatal(r.err)
+	}
+	if r.client.Transport.(*http.Transport).DialContext == nil {
+		t.Error("want SOCKS5 dialer to be preserved")
+	}
+}
+
+func TestTLSUnsupportedTransport(t *testing.T) {
+	client := &http.Client{Transport: RoundTripperFunc(func(req *http.Request) (*http.Response, error) {
+		return nil, nil
+	})}
+
+	resp := Get("https://example.com").Client(client).InsecureSkipVerify(true).Do()
+	if resp.Error() == nil {
+		t.Error("want error for unsupported transport, got nil")
+	}
+}
+
+// newTestClientCert creates a self-signed client certificate and returns it with its PEM encodings
+func newTestClientCert(t *testing.T) (tls.Certificate, []byte, []byte) {
+	t.Helper()
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "rq-test-client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cert, certPEM, keyPEM
+}
+
+func TestClientCert(t *testing.T) {
+	cert, certPEM, keyPEM := newTestClientCert(t)
+
+	clientCAs := x509.NewCertPool()
+	clientCAs.AppendCertsFromPEM(certPEM)
+
+	srv := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(r.TLS.PeerCertificates[0].Subject.CommonName))
+	}))
+	srv.TLS = &tls.Config{
+		ClientAuth: tls.RequireAndVerifyClientCert,
+		ClientCAs:  clientCAs,
+	}
+	srv.StartTLS()
+	defer srv.Close()
+
+	rootCAs := x509.NewCertPool()
+	rootCAs.AddCert(srv.Certificate())
+
+	t.Run("without certificate", func(t *testing.T) {
+		resp := Get(srv.URL).RootCAs(rootCAs).Do()
+		if resp.Error() == nil {
+			t.Error("want handshake error without client certificate, got nil")
+		}
+	})
+
+	t.Run("ClientCert", func(t *testing.T) {
+		resp := Get(srv.URL).RootCAs(rootCAs).ClientCert(cert).Do()
+		if resp.Error() != nil {
+			t.Fatal(resp.Error())
+		}
+
+		body, _ := resp.String()
+		if body != "rq-test-client" {
+			t.Errorf("want client CN rq-test-client, got %q", body)
+		}
+	})
+
+	t.Run("ClientCertFiles", func(t *testing.T) {
+		dir := t.TempDir()
+		certPath := filepath.Join(dir, "client.crt")
+		keyPath := filepath.Join(dir, "client.key")
+		if err := os.WriteFile(certPath, certPEM, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(keyPath, keyPEM, 0o600); err != nil {
+			t.Fatal(err)
+		}
+
+		resp := Get(srv.URL).ClientCertFiles(certPath, keyPath).RootCAs(rootCAs).Do()
+		if resp.Error() != nil {
+			t.Fatal(resp.Error())
+		}
+	})
+
+	t.Run("missing files", func(t *testing.T) {
+		resp := Get(srv.URL).ClientCertFiles("missing.crt", "missing.key").Do()
+		if resp.Error() == nil || !strings.Contains(resp.Error().Error(), "load client certificate") {
+			t.Errorf("want load error, got %v", resp.Error())
+		}
+	})
+
+	t.Run("coexists with proxy", func(t *testing.T) {
+		r := Get(srv.URL).ProxyURL("http://proxy.example.com:8080").RootCAs(rootCAs).ClientCert(cert)
+		if r.err != nil {
+			t.Fatal(r.err)
+		}
+
+		transport := r.client.Transport.(*http.Transport)
+		if transport.Proxy == nil {
+			t.Error("want proxy to be preserved")
+		}
+		if transport.TLSClientConfig.RootCAs != rootCAs {
+			t.Error("want root CAs to be preserved")
+		}
+		if len(transport.TLSClientConfig.Certificates) != 1 {
+			t.Errorf("want 1 client certificate, got %d", len(transport.TLSClientConfig.Certificates))
+		}
+
+		r = Get(srv.URL).ClientCert(cert).ProxyURL("http://proxy.example.com:8080")
+		if r.err != nil {
+			t.Fatal(r.err)
+		}
+
+		transport = r.client.Transport.(*http.Transport)
+		if len(transport.TLSClientConfig.Certificates) != 1 {
+			t.Error("want client certificate to survive setting a proxy afterwards")
+		}
+	})
+}
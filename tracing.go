@@ -0,0 +1,74 @@
+package rq
+
+import (
+	"context"
+	"net/http"
+)
+
+// Tracer is the tracing integration used by TracingMiddleware. It keeps rq
+// free of a tracing dependency: an adapter over an OpenTelemetry tracer and
+// propagator, or any other tracing library, implements it.
+type Tracer interface {
+	// Start starts a span for req and returns a context carrying it
+	Start(ctx context.Context, name string, req *http.Request) (context.Context, Span)
+	// Inject writes the trace context of ctx, such as a W3C traceparent, into header
+	Inject(ctx context.Context, header http.Header)
+}
+
+// Span is a span started by a Tracer
+type Span interface {
+	// SetStatus records the response status code
+	SetStatus(code int)
+	// RecordError records a failed round trip
+	RecordError(err error)
+	// End finishes the span
+	End()
+}
+
+// TracingMiddleware starts a span named "HTTP {METHOD}" around every round
+// trip, injects its trace context into the outgoing headers and records the
+// response status or error. Like MetricsMiddleware it wraps the client
+// transport, so apply it after Proxy or TLSConfig.
+func TracingMiddleware(tracer Tracer) Middleware {
+	return func(r *Request) *Request {
+		if r.err != nil {
+			return r
+		}
+
+		client := r.cloneClient()
+		client.Transport = &tracingTransport{base: client.Transport, tracer: tracer}
+		r.client = client
+
+		return r
+	}
+}
+
+// tracingTransport wraps round trips in spans
+type tracingTransport struct {
+	base   http.RoundTripper
+	tracer Tracer
+}
+
+// RoundTrip implements the RoundTripper interface
+func (t *tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	base := t.base
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	ctx, span := t.tracer.Start(req.Context(), "HTTP "+req.Method, req)
+	defer span.End()
+
+	// RoundTrippers must not modify the caller's request
+	req = req.Clone(ctx)
+	t.tracer.Inject(ctx, req.Header)
+
+	resp, err := base.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+
+	span.SetStatus(resp.StatusCode)
+	return resp, nil
+}
@@ -0,0 +1,81 @@
+package rq
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+type testSpan struct {
+	name   string
+	status int
+	err    error
+	ended  bool
+}
+
+func (s *testSpan) SetStatus(code int)    { s.status = code }
+func (s *testSpan) RecordError(err error) { s.err = err }
+func (s *testSpan) End()                  { s.ended = true }
+
+type spanKey struct{}
+
+type testTracer struct {
+	mu    sync.Mutex
+	spans []*testSpan
+}
+
+func (t *testTracer) Start(ctx context.Context, name string, req *http.Request) (context.Context, Span) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	span := &testSpan{name: name}
+	t.spans = append(t.spans, span)
+	return context.WithValue(ctx, spanKey{}, span), span
+}
+
+func (t *testTracer) Inject(ctx context.Context, header http.Header) {
+	if span, ok := ctx.Value(spanKey{}).(*testSpan); ok {
+		header.Set("Traceparent", "00-trace-"+span.name+"-01")
+	}
+}
+
+func TestTracingMiddleware(t *testing.T) {
+	var gotTraceparent string
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotTraceparent = r.Header.Get("Traceparent")
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer srv.Close()
+
+	tracer := &testTracer{}
+
+	resp := Put(srv.URL).Use(TracingMiddleware(tracer)).Do()
+	if resp.Error() != nil {
+		t.Fatal(resp.Error())
+	}
+
+	Get("http://127.0.0.1:1").Use(TracingMiddleware(tracer)).Do()
+
+	if len(tracer.spans) != 2 {
+		t.Fatalf("want 2 spans, got %d", len(tracer.spans))
+	}
+
+	span := tracer.spans[0]
+	if span.name != "HTTP PUT" {
+		t.Errorf("want span name %q, got %q", "HTTP PUT", span.name)
+	}
+	if span.status != http.StatusAccepted || !span.ended {
+		t.Errorf("want ended span with status 202, got %+v", span)
+	}
+	if gotTraceparent != "00-trace-HTTP PUT-01" {
+		t.Errorf("want injected traceparent, got %q", gotTraceparent)
+	}
+
+	failed := tracer.spans[1]
+	if failed.err == nil || !failed.ended {
+		t.Errorf("want ended span with error, got %+v", failed)
+	}
+}
@@ -0,0 +1,100 @@
+package rq
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// MaxIdleConnsPerHost creates a new request with the idle connection limit per host
+func MaxIdleConnsPerHost(n int) *Request {
+	return New().MaxIdleConnsPerHost(n)
+}
+
+// MaxIdleConnsPerHost sets the maximum number of idle connections kept per host.
+// Pooling only helps when requests share a client: apply the pool settings
+// once to a template request and derive requests from it with Clone, rather
+// than tuning a fresh transport for every request.
+func (r *Request) MaxIdleConnsPerHost(n int) *Request {
+	return r.updateTransport("configure connection pool", func(t *http.Transport) {
+		t.MaxIdleConnsPerHost = n
+	})
+}
+
+// MaxIdleConns creates a new request with the total idle connection limit
+func MaxIdleConns(n int) *Request {
+	return New().MaxIdleConns(n)
+}
+
+// MaxIdleConns sets the maximum number of idle connections across all hosts
+func (r *Request) MaxIdleConns(n int) *Request {
+	return r.updateTransport("configure connection pool", func(t *http.Transport) {
+		t.MaxIdleConns = n
+	})
+}
+
+// MaxConnsPerHost creates a new request with the connection limit per host
+func MaxConnsPerHost(n int) *Request {
+	return New().MaxConnsPerHost(n)
+}
+
+// MaxConnsPerHost limits the total number of connections per host, including
+// those in use. Zero means no limit.
+func (r *Request) MaxConnsPerHost(n int) *Request {
+	return r.updateTransport("configure connection pool", func(t *http.Transport) {
+		t.MaxConnsPerHost = n
+	})
+}
+
+// IdleConnTimeout creates a new request with the idle connection timeout
+func IdleConnTimeout(d time.Duration) *Request {
+	return New().IdleConnTimeout(d)
+}
+
+// IdleConnTimeout sets how long an idle connection stays in the pool
+func (r *Request) IdleConnTimeout(d time.Duration) *Request {
+	return r.updateTransport("configure connection pool", func(t *http.Transport) {
+		t.IdleConnTimeout = d
+	})
+}
+
+// updateTransport applies fn to a cloned client and transport, keeping proxy
+// and TLS settings applied earlier
+func (r *Request) updateTransport(action string, fn func(*http.Transport)) *Request {
+	if r.err != nil {
+		return r
+	}
+
+	client := r.cloneClient()
+
+	var transport *http.Transport
+	switch t := client.Transport.(type) {
+	case nil:
+		transport = http.DefaultTransport.(*http.Transport).Clone()
+	case *http.Transport:
+		transport = t.Clone()
+	default:
+		r.err = fmt.Errorf("%s: unsupported transport %T", action, client.Transport)
+		return r
+	}
+
+	fn(transport)
+
+	client.Transport = transport
+	r.client = client
+	return r
+}
+
+// ResponseHeaderTimeout creates a new request with a time-to-first-byte limit
+func ResponseHeaderTimeout(d time.Duration) *Request {
+	return New().ResponseHeaderTimeout(d)
+}
+
+// ResponseHeaderTimeout limits how long to wait for the response headers
+// once the request is written. Unlike Timeout it does not cover reading
+// the body, so large or streamed downloads are not cut short.
+func (r *Request) ResponseHeaderTimeout(d time.Duration) *Request {
+	return r.updateTransport("configure response header timeout", func(t *http.Transport) {
+		t.ResponseHeaderTimeout = d
+	})
+}
@@ -0,0 +1,106 @@
+package rq
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTransportPoolOptions(t *testing.T) {
+	r := New().
+		ProxyURL("http://proxy.example.com:8080").
+		InsecureSkipVerify(true).
+		MaxIdleConnsPerHost(32).
+		MaxIdleConns(128).
+		MaxConnsPerHost(64).
+		IdleConnTimeout(45 * time.Second)
+	if r.err != nil {
+		t.Fatal(r.err)
+	}
+
+	transport := getTransport(r.client)
+	if transport == nil {
+		t.Fatal("want *http.Transport, got nil")
+	}
+
+	if transport.MaxIdleConnsPerHost != 32 {
+		t.Errorf("want MaxIdleConnsPerHost 32, got %d", transport.MaxIdleConnsPerHost)
+	}
+	if transport.MaxIdleConns != 128 {
+		t.Errorf("want MaxIdleConns 128, got %d", transport.MaxIdleConns)
+	}
+	if transport.MaxConnsPerHost != 64 {
+		t.Errorf("want MaxConnsPerHost 64, got %d", transport.MaxConnsPerHost)
+	}
+	if transport.IdleConnTimeout != 45*time.Second {
+		t.Errorf("want IdleConnTimeout 45s, got %v", transport.IdleConnTimeout)
+	}
+
+	if transport.Proxy == nil {
+		t.Error("want proxy to be kept")
+	}
+	if transport.TLSClientConfig == nil || !transport.TLSClientConfig.InsecureSkipVerify {
+		t.Error("want TLS config to be kept")
+	}
+}
+
+func TestTransportPoolOptionsDoNotShareTransport(t *testing.T) {
+	base := New().MaxIdleConnsPerHost(4)
+	derived := base.Clone().MaxIdleConnsPerHost(8)
+
+	if got := getTransport(base.client).MaxIdleConnsPerHost; got != 4 {
+		t.Errorf("want base MaxIdleConnsPerHost 4, got %d", got)
+	}
+	if got := getTransport(derived.client).MaxIdleConnsPerHost; got != 8 {
+		t.Errorf("want derived MaxIdleConnsPerHost 8, got %d", got)
+	}
+}
+
+func TestTransportPoolOptionsUnsupportedTransport(t *testing.T) {
+	client := &http.Client{Transport: RoundTripperFunc(func(*http.Request) (*http.Response, error) {
+		return nil, nil
+	})}
+
+	if err := Client(client).MaxConnsPerHost(1).err; err == nil {
+		t.Error("want error for custom transport, got nil")
+	}
+}
+
+func TestResponseHeaderTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow-headers" {
+			time.Sleep(200 * time.Millisecond)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+		w.(http.Flusher).Flush()
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte("done"))
+	}))
+	defer srv.Close()
+
+	t.Run("slow headers", func(t *testing.T) {
+		resp := Get(srv.URL + "/slow-headers").ResponseHeaderTimeout(50 * time.Millisecond).Do()
+		if resp.Error() == nil {
+			t.Error("want timeout error, got nil")
+		}
+	})
+
+	t.Run("slow body", func(t *testing.T) {
+		body, err := Get(srv.URL + "/slow-body").ResponseHeaderTimeout(50 * time.Millisecond).Do().String()
+		if err != nil {
+			t.Fatal(err)
+		}
+		if body != "done" {
+			t.Errorf("want body done, got %q", body)
+		}
+	})
+
+	t.Run("keeps proxy", func(t *testing.T) {
+		r := New().ProxyURL("http://proxy.example.com:8080").ResponseHeaderTimeout(time.Second)
+		if transport := getTransport(r.client); transport == nil || transport.Proxy == nil {
+			t.Error("want proxy to be kept")
+		}
+	})
+}
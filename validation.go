@@ -1,10 +1,16 @@
 package rq
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"mime"
+	"reflect"
 	"regexp"
+	"slices"
 	"strings"
+	"time"
 )
 
 // Validator is a function that validates a response
@@ -50,6 +56,32 @@ func (validateNamespace) StatusCode(expected int) Validator {
 	}
 }
 
+// StatusInRange validates that the status code is between min and max inclusive
+func (validateNamespace) StatusInRange(minCode, maxCode int) Validator {
+	return func(r *Response) error {
+		if r.err != nil {
+			return r.err
+		}
+		if r.StatusCode < minCode || r.StatusCode > maxCode {
+			return fmt.Errorf("expected status in range %d-%d, got %d", minCode, maxCode, r.StatusCode)
+		}
+		return nil
+	}
+}
+
+// StatusIn validates that the status code is one of the given codes
+func (validateNamespace) StatusIn(codes ...int) Validator {
+	return func(r *Response) error {
+		if r.err != nil {
+			return r.err
+		}
+		if !slices.Contains(codes, r.StatusCode) {
+			return fmt.Errorf("expected status in %v, got %d", codes, r.StatusCode)
+		}
+		return nil
+	}
+}
+
 // Header validates that the response has a specific header with expected value
 func (validateNamespace) Header(key, expectedValue string) Validator {
 	return func(r *Response) error {
@@ -79,6 +111,39 @@ func (validateNamespace) HeaderExists(key string) Validator {
 	}
 }
 
+// ContentType validates the response media type, ignoring parameters such as charset
+func (validateNamespace) ContentType(expected string) Validator {
+	return func(r *Response) error {
+		if r.err != nil {
+			return r.err
+		}
+
+		actual := r.Header.Get("Content-Type")
+		mediaType, _, err := mime.ParseMediaType(actual)
+		if err != nil || !strings.EqualFold(mediaType, expected) {
+			return fmt.Errorf("expected content type %q, got %q", expected, actual)
+		}
+
+		return nil
+	}
+}
+
+// ContentTypeContains validates that the Content-Type header contains a substring
+func (validateNamespace) ContentTypeContains(substr string) Validator {
+	return func(r *Response) error {
+		if r.err != nil {
+			return r.err
+		}
+
+		actual := r.Header.Get("Content-Type")
+		if !strings.Contains(strings.ToLower(actual), strings.ToLower(substr)) {
+			return fmt.Errorf("expected content type to contain %q, got %q", substr, actual)
+		}
+
+		return nil
+	}
+}
+
 // BodyContains validates that the response body contains a specific substring
 func (validateNamespace) BodyContains(substr string) Validator {
 	return func(r *Response) error {
@@ -114,6 +179,93 @@ func (validateNamespace) BodyMatches(pattern string) Validator {
 	}
 }
 
+// JSONPath validates that the value at a JSONPath expression equals expected.
+// Strings are compared as is, other values in their JSON form.
+func (validateNamespace) JSONPath(path, expected string) Validator {
+	return func(r *Response) error {
+		if r.err != nil {
+			return r.err
+		}
+
+		value, err := r.JSONPath(path)
+		if err != nil {
+			return err
+		}
+
+		if actual := formatJSONValue(value); actual != expected {
+			return fmt.Errorf("expected %s to be %q, got %q", path, expected, actual)
+		}
+
+		return nil
+	}
+}
+
+// JSONShape validates that the response body decodes into a fresh value of
+// the template's type with no unknown fields, so renamed or added fields
+// are reported. The template is only used for its type; a pointer is
+// followed to its element type.
+func (validateNamespace) JSONShape(template any) Validator {
+	t := reflect.TypeOf(template)
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	return func(r *Response) error {
+		if r.err != nil {
+			return r.err
+		}
+		if t == nil {
+			return errors.New("JSON shape template must not be nil")
+		}
+
+		dec := json.NewDecoder(bytes.NewReader(r.body))
+		dec.DisallowUnknownFields()
+
+		if err := dec.Decode(reflect.New(t).Interface()); err != nil {
+			return fmt.Errorf("response body does not match %s: %w", t, err)
+		}
+		if dec.More() {
+			return fmt.Errorf("response body does not match %s: unexpected data after JSON value", t)
+		}
+
+		return nil
+	}
+}
+
+// MaxLatency validates that the request, including reading the body, took
+// at most d according to Response.Timing. Responses without recorded
+// timing fail rather than pass silently.
+func (validateNamespace) MaxLatency(d time.Duration) Validator {
+	return func(r *Response) error {
+		if r.err != nil {
+			return r.err
+		}
+
+		timing := r.Timing()
+		if timing.Start.IsZero() || timing.Total <= 0 {
+			return fmt.Errorf("expected latency at most %s, but no timing was recorded", d)
+		}
+		if timing.Total > d {
+			return fmt.Errorf("expected latency at most %s, got %s", d, timing.Total)
+		}
+		return nil
+	}
+}
+
+// Satisfies validates that the response satisfies an arbitrary predicate.
+// The label is used in the error message when the predicate returns false.
+func (validateNamespace) Satisfies(label string, pred func(*Response) bool) Validator {
+	return func(r *Response) error {
+		if r.err != nil {
+			return r.err
+		}
+		if !pred(r) {
+			return fmt.Errorf("validation %q failed", label)
+		}
+		return nil
+	}
+}
+
 // All combines multiple validators - all must pass
 func (validateNamespace) All(validators ...Validator) Validator {
 	return func(r *Response) error {
@@ -154,11 +306,20 @@ func (validateNamespace) Any(validators ...Validator) Validator {
 	}
 }
 
-// Not inverts a validator (success becomes failure and vice versa)
-func (validateNamespace) Not(validator Validator) Validator {
+// Not inverts a validator (success becomes failure and vice versa).
+// An optional description replaces the generic failure message.
+func (v validateNamespace) Not(validator Validator, description ...string) Validator {
+	if len(description) > 0 {
+		return v.NotWithMessage(validator, strings.Join(description, " "))
+	}
+	return v.NotWithMessage(validator, "expected validation to fail but it passed")
+}
+
+// NotWithMessage inverts a validator, failing with msg when it passes
+func (validateNamespace) NotWithMessage(validator Validator, msg string) Validator {
 	return func(r *Response) error {
 		if err := validator(r); err == nil {
-			return fmt.Errorf("expected validation to fail but it passed")
+			return errors.New(msg)
 		}
 		return nil
 	}
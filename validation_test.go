@@ -5,6 +5,7 @@ import (
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/k64z/rq"
 )
@@ -91,6 +92,75 @@ func TestOKValidator(t *testing.T) {
 	}
 }
 
+func TestStatusRangeValidators(t *testing.T) {
+	tests := map[string]struct {
+		serverStatus int
+		validator    rq.Validator
+		wantErr      string
+	}{
+		"in range": {
+			serverStatus: http.StatusFound,
+			validator:    rq.Validate.StatusInRange(200, 399),
+		},
+		"range is inclusive": {
+			serverStatus: http.StatusBadRequest,
+			validator:    rq.Validate.StatusInRange(400, 499),
+		},
+		"out of range": {
+			serverStatus: http.StatusInternalServerError,
+			validator:    rq.Validate.StatusInRange(400, 499),
+			wantErr:      "expected status in range 400-499, got 500",
+		},
+		"in set": {
+			serverStatus: http.StatusAccepted,
+			validator:    rq.Validate.StatusIn(http.StatusOK, http.StatusAccepted),
+		},
+		"not in set": {
+			serverStatus: http.StatusNoContent,
+			validator:    rq.Validate.StatusIn(http.StatusOK, http.StatusAccepted),
+			wantErr:      "expected status in [200 202], got 204",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.serverStatus)
+			}))
+			defer ts.Close()
+
+			client := &http.Client{
+				CheckRedirect: func(req *http.Request, via []*http.Request) error {
+					return http.ErrUseLastResponse
+				},
+			}
+
+			var laterCalled bool
+			resp := rq.Get(ts.URL).
+				Client(client).
+				Validate(tt.validator, func(r *rq.Response) error {
+					laterCalled = true
+					return nil
+				}).
+				Do()
+
+			if tt.wantErr == "" {
+				if resp.Error() != nil {
+					t.Errorf("want no error, got %v", resp.Error())
+				}
+				return
+			}
+
+			if resp.Error() == nil || !strings.Contains(resp.Error().Error(), tt.wantErr) {
+				t.Errorf("want error containing %q, got %v", tt.wantErr, resp.Error())
+			}
+			if laterCalled {
+				t.Error("later validator should not run after a failure")
+			}
+		})
+	}
+}
+
 func TestHeaderValidator(t *testing.T) {
 	tests := map[string]struct {
 		serverHeader map[string]string
@@ -191,6 +261,71 @@ func TestHeaderExistsValidator(t *testing.T) {
 	}
 }
 
+func TestContentTypeValidator(t *testing.T) {
+	tests := map[string]struct {
+		contentType string
+		validator   rq.Validator
+		wantErr     bool
+	}{
+		"exact match": {
+			contentType: "application/json",
+			validator:   rq.Validate.ContentType("application/json"),
+		},
+		"ignores parameters": {
+			contentType: "application/json; charset=utf-8",
+			validator:   rq.Validate.ContentType("application/json"),
+		},
+		"case insensitive": {
+			contentType: "Application/JSON",
+			validator:   rq.Validate.ContentType("application/json"),
+		},
+		"mismatch": {
+			contentType: "text/html",
+			validator:   rq.Validate.ContentType("application/json"),
+			wantErr:     true,
+		},
+		"contains": {
+			contentType: "application/problem+json",
+			validator:   rq.Validate.ContentTypeContains("json"),
+		},
+		"does not contain": {
+			contentType: "text/plain",
+			validator:   rq.Validate.ContentTypeContains("json"),
+			wantErr:     true,
+		},
+		"composes with Not": {
+			contentType: "text/html",
+			validator:   rq.Validate.Not(rq.Validate.ContentType("application/json")),
+		},
+		"composes with Any": {
+			contentType: "application/xml",
+			validator: rq.Validate.Any(
+				rq.Validate.ContentType("application/json"),
+				rq.Validate.ContentType("application/xml"),
+			),
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tt.contentType)
+				w.WriteHeader(http.StatusOK)
+			}))
+			defer ts.Close()
+
+			resp := rq.Get(ts.URL).Validate(tt.validator).Do()
+
+			if tt.wantErr && resp.Error() == nil {
+				t.Error("want validation error, got nil")
+			}
+			if !tt.wantErr && resp.Error() != nil {
+				t.Errorf("want no error, got %v", resp.Error())
+			}
+		})
+	}
+}
+
 func TestBodyContainsValidator(t *testing.T) {
 	tests := map[string]struct {
 		body      string
@@ -391,6 +526,253 @@ func TestNotValidator(t *testing.T) {
 		if resp.Error() == nil {
 			t.Error("want validation error, got nil")
 		}
+		if !strings.Contains(resp.Error().Error(), "expected validation to fail") {
+			t.Errorf("want default message, got %v", resp.Error())
+		}
+	})
+
+	t.Run("custom message", func(t *testing.T) {
+		tests := map[string]rq.Validator{
+			"description":      rq.Validate.Not(rq.Validate.BodyContains("hello"), "expected body not to contain 'hello'"),
+			"not with message": rq.Validate.NotWithMessage(rq.Validate.BodyContains("hello"), "expected body not to contain 'hello'"),
+		}
+
+		for name, validator := range tests {
+			t.Run(name, func(t *testing.T) {
+				resp := rq.New().URL(ts.URL).Validate(validator).Do()
+				if resp.Error() == nil {
+					t.Fatal("want validation error, got nil")
+				}
+				if !strings.Contains(resp.Error().Error(), "expected body not to contain 'hello'") {
+					t.Errorf("want custom message, got %v", resp.Error())
+				}
+			})
+		}
+	})
+}
+
+func TestJSONPathValidator(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"data":{"items":[{"id":42,"name":"answer","active":true}]}}`))
+	}))
+	defer ts.Close()
+
+	tests := map[string]struct {
+		path     string
+		expected string
+		wantErr  string
+	}{
+		"number matches": {
+			path:     "$.data.items[0].id",
+			expected: "42",
+		},
+		"string matches": {
+			path:     "$.data.items[0].name",
+			expected: "answer",
+		},
+		"bool matches": {
+			path:     "$.data.items[0].active",
+			expected: "true",
+		},
+		"mismatch reports actual": {
+			path:     "$.data.items[0].id",
+			expected: "7",
+			wantErr:  `got "42"`,
+		},
+		"invalid expression": {
+			path:     "data.items",
+			expected: "x",
+			wantErr:  "invalid JSONPath",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := rq.Get(ts.URL).
+				Validate(rq.Validate.JSONPath(tt.path, tt.expected)).
+				Do()
+
+			if tt.wantErr == "" {
+				if resp.Error() != nil {
+					t.Errorf("want no error, got %v", resp.Error())
+				}
+				return
+			}
+
+			if resp.Error() == nil {
+				t.Fatal("want validation error, got nil")
+			}
+			if !strings.Contains(resp.Error().Error(), tt.wantErr) {
+				t.Errorf("want error containing %q, got %v", tt.wantErr, resp.Error())
+			}
+		})
+	}
+}
+
+func TestJSONShapeValidator(t *testing.T) {
+	type item struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	}
+
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch r.URL.Path {
+		case "/renamed":
+			w.Write([]byte(`{"id":1,"title":"x"}`))
+		case "/wrong-type":
+			w.Write([]byte(`{"id":"1","name":"x"}`))
+		default:
+			w.Write([]byte(`{"id":1,"name":"x"}`))
+		}
+	}))
+	defer ts.Close()
+
+	tests := map[string]struct {
+		path     string
+		template any
+		wantErr  string
+	}{
+		"matches": {
+			path:     "/",
+			template: item{},
+		},
+		"pointer template": {
+			path:     "/",
+			template: &item{},
+		},
+		"unknown field": {
+			path:     "/renamed",
+			template: item{},
+			wantErr:  `unknown field "title"`,
+		},
+		"wrong type": {
+			path:     "/wrong-type",
+			template: item{},
+			wantErr:  "item.id",
+		},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := rq.Get(ts.URL + tt.path).
+				Validate(rq.Validate.JSONShape(tt.template)).
+				Do()
+
+			if tt.wantErr == "" {
+				if resp.Error() != nil {
+					t.Errorf("want no error, got %v", resp.Error())
+				}
+				return
+			}
+
+			if resp.Error() == nil {
+				t.Fatal("want validation error, got nil")
+			}
+			if !strings.Contains(resp.Error().Error(), tt.wantErr) {
+				t.Errorf("want error containing %q, got %v", tt.wantErr, resp.Error())
+			}
+		})
+	}
+}
+
+func TestMaxLatencyValidator(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			time.Sleep(50 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	tests := map[string]struct {
+		path    string
+		max     time.Duration
+		wantErr string
+	}{
+		"within limit": {path: "/", max: time.Second},
+		"too slow":     {path: "/slow", max: 10 * time.Millisecond, wantErr: "expected latency at most 10ms"},
+	}
+
+	for name, tt := range tests {
+		t.Run(name, func(t *testing.T) {
+			resp := rq.New().
+				URL(ts.URL + tt.path).
+				Validate(rq.Validate.MaxLatency(tt.max)).
+				Do()
+
+			if tt.wantErr == "" {
+				if resp.Error() != nil {
+					t.Errorf("want no error, got %v", resp.Error())
+				}
+				return
+			}
+			if resp.Error() == nil || !strings.Contains(resp.Error().Error(), tt.wantErr) {
+				t.Errorf("want error containing %q, got %v", tt.wantErr, resp.Error())
+			}
+		})
+	}
+
+	t.Run("no timing", func(t *testing.T) {
+		resp := &rq.Response{Response: &http.Response{StatusCode: http.StatusOK}}
+		err := rq.Validate.MaxLatency(time.Second)(resp)
+		if err == nil || !strings.Contains(err.Error(), "no timing was recorded") {
+			t.Errorf("want missing timing error, got %v", err)
+		}
+	})
+}
+
+func TestSatisfiesValidator(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("X-Total-Count", "42")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer ts.Close()
+
+	hasTotal := func(r *rq.Response) bool {
+		return r.Header.Get("X-Total-Count") != ""
+	}
+
+	t.Run("predicate passes", func(t *testing.T) {
+		resp := rq.New().
+			URL(ts.URL).
+			Validate(rq.Validate.Satisfies("has total count", hasTotal)).
+			Do()
+		if resp.Error() != nil {
+			t.Errorf("want no error, got %v", resp.Error())
+		}
+	})
+
+	t.Run("predicate fails with label", func(t *testing.T) {
+		resp := rq.New().
+			URL(ts.URL).
+			Validate(rq.Validate.Satisfies("is empty", func(r *rq.Response) bool {
+				return r.ContentLength > 0
+			})).
+			Do()
+		if resp.Error() == nil {
+			t.Fatal("want validation error, got nil")
+		}
+		if !strings.Contains(resp.Error().Error(), `validation "is empty" failed`) {
+			t.Errorf("want label in error, got %v", resp.Error())
+		}
+	})
+
+	t.Run("transport error skips predicate", func(t *testing.T) {
+		var called bool
+		validator := rq.Validate.Satisfies("never", func(r *rq.Response) bool {
+			called = true
+			return true
+		})
+
+		resp := rq.New().URL("http://127.0.0.1:1").Do()
+		if err := validator(resp); err == nil {
+			t.Error("want transport error, got nil")
+		}
+		if called {
+			t.Error("predicate should not be called on transport error")
+		}
 	})
 }
 
@@ -0,0 +1,240 @@
+package rq
+
+import (
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ValuesEncoder is implemented by types that encode themselves into
+// url.Values when used as a field of a struct passed to QueryStruct or
+// BodyFormStruct. key is the name the field would be encoded under.
+type ValuesEncoder interface {
+	EncodeValues(key string, v *url.Values) error
+}
+
+var (
+	timeType          = reflect.TypeFor[time.Time]()
+	valuesEncoderType = reflect.TypeFor[ValuesEncoder]()
+)
+
+// fieldOptions holds the encoding options of a struct field
+type fieldOptions struct {
+	name      string
+	omitEmpty bool
+	unix      bool
+	layout    string
+	named     bool
+}
+
+// structValues encodes the exported fields of a struct into url.Values.
+// Field names are taken from the first of the given tags that is present,
+// falling back to the field name. A tag of "-" skips the field and the
+// omitempty option skips zero values. Slices produce repeated values and
+// nil pointers are skipped. Fields of embedded structs are promoted, while
+// nested structs are flattened as parent[child]. time.Time is formatted as
+// RFC 3339, as Unix seconds with the unix option, or with the layout given
+// in a `layout` tag. Fields implementing ValuesEncoder encode themselves.
+func structValues(v any, tags ...string) (url.Values, error) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return url.Values{}, nil
+		}
+		rv = rv.Elem()
+	}
+
+	if rv.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("expected struct, got %s", rv.Kind())
+	}
+
+	values := make(url.Values)
+	if err := encodeStruct(values, "", rv, tags); err != nil {
+		return nil, err
+	}
+
+	return values, nil
+}
+
+// encodeStruct adds the fields of rv to values, prefixing nested keys
+func encodeStruct(values url.Values, prefix string, rv reflect.Value, tags []string) error {
+	rt := rv.Type()
+
+	for i := 0; i < rt.NumField(); i++ {
+		field := rt.Field(i)
+		opts := fieldTag(field, tags)
+		if opts.name == "-" {
+			continue
+		}
+
+		fv := rv.Field(i)
+
+		if field.Anonymous && !opts.named {
+			embedded := fv
+			if embedded.Kind() == reflect.Pointer {
+				if embedded.IsNil() {
+					continue
+				}
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct && embedded.Type() != timeType && !isValuesEncoder(fv) {
+				if err := encodeStruct(values, prefix, embedded, tags); err != nil {
+					return err
+				}
+				continue
+			}
+		}
+
+		if !field.IsExported() {
+			continue
+		}
+		if opts.omitEmpty && fv.IsZero() {
+			continue
+		}
+
+		key := opts.name
+		if prefix != "" {
+			key = prefix + "[" + key + "]"
+		}
+
+		if err := encodeValue(values, key, fv, opts, tags); err != nil {
+			return fmt.Errorf("field %s: %w", field.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// encodeValue adds v to values under key
+func encodeValue(values url.Values, key string, v reflect.Value, opts fieldOptions, tags []string) error {
+	if isValuesEncoder(v) {
+		if v.Kind() == reflect.Pointer && v.IsNil() {
+			return nil
+		}
+		return valuesEncoder(v).EncodeValues(key, &values)
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+		return encodeValue(values, key, v.Elem(), opts, tags)
+	case reflect.Slice, reflect.Array:
+		for j := 0; j < v.Len(); j++ {
+			if err := encodeValue(values, key, v.Index(j), opts, tags); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if v.Type() == timeType && v.CanInterface() {
+		values.Add(key, formatTime(v.Interface().(time.Time), opts))
+		return nil
+	}
+
+	if v.Kind() == reflect.Struct {
+		if s, ok := stringer(v); ok {
+			values.Add(key, s.String())
+			return nil
+		}
+		return encodeStruct(values, key, v, tags)
+	}
+
+	s, err := formatValue(v)
+	if err != nil {
+		return err
+	}
+	values.Add(key, s)
+
+	return nil
+}
+
+// isValuesEncoder reports whether v or its address implements ValuesEncoder
+func isValuesEncoder(v reflect.Value) bool {
+	if !v.CanInterface() {
+		return false
+	}
+	return v.Type().Implements(valuesEncoderType) ||
+		(v.CanAddr() && v.Addr().Type().Implements(valuesEncoderType))
+}
+
+// valuesEncoder returns v as a ValuesEncoder
+func valuesEncoder(v reflect.Value) ValuesEncoder {
+	if v.Type().Implements(valuesEncoderType) {
+		return v.Interface().(ValuesEncoder)
+	}
+	return v.Addr().Interface().(ValuesEncoder)
+}
+
+// formatTime formats t according to the field options
+func formatTime(t time.Time, opts fieldOptions) string {
+	switch {
+	case opts.unix:
+		return strconv.FormatInt(t.Unix(), 10)
+	case opts.layout != "":
+		return t.Format(opts.layout)
+	default:
+		return t.Format(time.RFC3339)
+	}
+}
+
+// fieldTag returns the encoding options for a struct field
+func fieldTag(field reflect.StructField, tags []string) fieldOptions {
+	opts := fieldOptions{name: field.Name, layout: field.Tag.Get("layout")}
+
+	for _, key := range tags {
+		tag, ok := field.Tag.Lookup(key)
+		if !ok {
+			continue
+		}
+
+		name, rest, _ := strings.Cut(tag, ",")
+		if name != "" {
+			opts.name = name
+			opts.named = true
+		}
+
+		rest = "," + rest + ","
+		opts.omitEmpty = strings.Contains(rest, ",omitempty,")
+		opts.unix = strings.Contains(rest, ",unix,")
+		break
+	}
+
+	return opts
+}
+
+// formatValue formats a scalar value as a string
+func formatValue(v reflect.Value) (string, error) {
+	if s, ok := stringer(v); ok {
+		return s.String(), nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return v.String(), nil
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool()), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.FormatInt(v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.FormatUint(v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf("unsupported type %s", v.Type())
+	}
+}
+
+// stringer returns v as a fmt.Stringer if it implements it and is accessible
+func stringer(v reflect.Value) (fmt.Stringer, bool) {
+	if !v.CanInterface() {
+		return nil, false
+	}
+	s, ok := v.Interface().(fmt.Stringer)
+	return s, ok
+}
@@ -0,0 +1,115 @@
+package rq
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+type bbox struct {
+	MinX, MinY, MaxX, MaxY float64
+}
+
+func (b bbox) EncodeValues(key string, v *url.Values) error {
+	v.Set(key, strings.Join([]string{
+		formatFloat(b.MinX), formatFloat(b.MinY), formatFloat(b.MaxX), formatFloat(b.MaxY),
+	}, ","))
+	return nil
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+type Paging struct {
+	Page    int `url:"page"`
+	PerPage int `url:"per_page,omitempty"`
+}
+
+type filter struct {
+	Status string `url:"status"`
+}
+
+type searchParams struct {
+	Paging
+	*filter
+
+	Query   string    `url:"q"`
+	Tags    []string  `url:"tag"`
+	Limit   *int      `url:"limit"`
+	Offset  *int      `url:"offset"`
+	Since   time.Time `url:"since"`
+	Until   time.Time `url:"until,unix"`
+	Day     time.Time `url:"day" layout:"2006-01-02"`
+	Area    bbox      `url:"bbox"`
+	Owner   owner     `url:"owner"`
+	Skipped string    `url:"-"`
+	Empty   string    `url:"empty,omitempty"`
+	NoTag   bool
+	private string
+}
+
+type owner struct {
+	Name string `url:"name"`
+	ID   int    `url:"id"`
+}
+
+func TestStructValues(t *testing.T) {
+	limit := 10
+	ts := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+
+	got, err := structValues(searchParams{
+		Paging:  Paging{Page: 2},
+		filter:  &filter{Status: "open"},
+		Query:   "go http",
+		Tags:    []string{"a", "b"},
+		Limit:   &limit,
+		Since:   ts,
+		Until:   ts,
+		Day:     ts,
+		Area:    bbox{1, 2, 3.5, 4},
+		Owner:   owner{Name: "k64z", ID: 7},
+		Skipped: "x",
+		NoTag:   true,
+		private: "x",
+	}, "url")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := url.Values{
+		"page":        {"2"},
+		"status":      {"open"},
+		"q":           {"go http"},
+		"tag":         {"a", "b"},
+		"limit":       {"10"},
+		"since":       {"2024-03-01T12:00:00Z"},
+		"until":       {"1709294400"},
+		"day":         {"2024-03-01"},
+		"bbox":        {"1,2,3.5,4"},
+		"owner[name]": {"k64z"},
+		"owner[id]":   {"7"},
+		"NoTag":       {"true"},
+	}
+
+	if got.Encode() != want.Encode() {
+		t.Errorf("want\n%s\ngot\n%s", want.Encode(), got.Encode())
+	}
+}
+
+func TestStructValuesErrors(t *testing.T) {
+	tests := map[string]any{
+		"not a struct":     "string",
+		"unsupported type": struct{ C chan int }{},
+	}
+
+	for name, v := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := structValues(v, "url"); err == nil {
+				t.Error("want error, got nil")
+			}
+		})
+	}
+}
@@ -0,0 +1,363 @@
+package rq
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1" // #nosec G505 -- required by the RFC 6455 handshake
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+)
+
+// websocketGUID is appended to the handshake key to compute the accept value
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// WebSocket message types
+const (
+	WebSocketText   = 1
+	WebSocketBinary = 2
+)
+
+// defaultWebSocketReadLimit caps received messages when MaxBodySize is unset
+const defaultWebSocketReadLimit = 32 << 20
+
+// WebSocket frame opcodes
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xa
+)
+
+// WebSocketCloseError is returned by ReadMessage once the server closes
+// the connection
+type WebSocketCloseError struct {
+	Code   int
+	Reason string
+}
+
+// Error implements the error interface
+func (e *WebSocketCloseError) Error() string {
+	if e.Reason == "" {
+		return fmt.Sprintf("websocket: closed with code %d", e.Code)
+	}
+	return fmt.Sprintf("websocket: closed with code %d: %s", e.Code, e.Reason)
+}
+
+// WebSocketConn is a client WebSocket connection implementing the RFC 6455
+// framing. Pings are answered automatically while reading. One goroutine
+// may read while others write.
+type WebSocketConn struct {
+	conn      io.ReadWriteCloser
+	reader    *bufio.Reader
+	readLimit int64
+
+	writeMu   sync.Mutex
+	closeSent bool
+}
+
+// WebSocket opens a WebSocket connection to the request URL, which may use
+// the ws, wss, http or https scheme. The handshake goes through the request
+// client, so headers, authentication, cookies, proxy and TLS settings apply.
+// Timeout, or the client timeout, bounds the handshake only; MaxBodySize
+// limits the size of received messages, defaulting to 32 MiB. The returned Response carries the
+// 101 status and headers, or the rejected handshake response.
+func (r *Request) WebSocket(ctx context.Context) (*WebSocketConn, *Response, error) {
+	if r.err != nil {
+		return nil, nil, r.err
+	}
+
+	var nonce [16]byte
+	if _, err := rand.Read(nonce[:]); err != nil {
+		return nil, nil, fmt.Errorf("websocket: generate key: %w", err)
+	}
+	key := base64.StdEncoding.EncodeToString(nonce[:])
+
+	req := r.Clone()
+	req.method = http.MethodGet
+	req.url = websocketURL(r.url)
+	req.teeResponse = nil
+	req.maxBodySize = 0
+	req.header().Set("Connection", "Upgrade")
+	req.header().Set("Upgrade", "websocket")
+	req.header().Set("Sec-WebSocket-Version", "13")
+	req.header().Set("Sec-WebSocket-Key", key)
+
+	// http.Client.Timeout would wrap the upgraded connection in a read-only
+	// body, so the handshake is bounded through the context instead
+	client := req.cloneClient()
+	if req.timeout == 0 {
+		req.timeout = client.Timeout
+	}
+	client.Timeout = 0
+	req.client = client
+
+	resp := req.do(ctx, true)
+	if resp.err != nil {
+		return nil, resp, resp.err
+	}
+
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		_ = resp.Body.Close()
+		return nil, resp, fmt.Errorf("websocket: handshake failed with status %s", resp.Status)
+	}
+
+	if err := checkWebSocketAccept(resp.Header, key); err != nil {
+		_ = resp.Body.Close()
+		return nil, resp, err
+	}
+
+	body, ok := resp.Body.(*cancelOnClose)
+	if !ok {
+		_ = resp.Body.Close()
+		return nil, resp, errors.New("websocket: connection is not writable")
+	}
+	conn, ok := body.ReadCloser.(io.ReadWriteCloser)
+	if !ok {
+		_ = resp.Body.Close()
+		return nil, resp, errors.New("websocket: connection is not writable")
+	}
+
+	return &WebSocketConn{
+		conn: struct {
+			io.Reader
+			io.Writer
+			io.Closer
+		}{conn, conn, body},
+		reader:    bufio.NewReader(conn),
+		readLimit: r.maxBodySize,
+	}, resp, nil
+}
+
+// websocketURL maps ws and wss URLs to the http and https schemes
+func websocketURL(rawURL string) string {
+	scheme, rest, ok := strings.Cut(rawURL, "://")
+	if !ok {
+		return rawURL
+	}
+
+	switch strings.ToLower(scheme) {
+	case "ws":
+		return "http://" + rest
+	case "wss":
+		return "https://" + rest
+	default:
+		return rawURL
+	}
+}
+
+// checkWebSocketAccept verifies the server's handshake response headers
+func checkWebSocketAccept(header http.Header, key string) error {
+	if !strings.EqualFold(header.Get("Upgrade"), "websocket") {
+		return errors.New("websocket: missing Upgrade: websocket header")
+	}
+	if !headerHasToken(header, "Connection", "upgrade") {
+		return errors.New("websocket: missing Connection: upgrade header")
+	}
+
+	h := sha1.New() // #nosec G401 -- required by the RFC 6455 handshake
+	h.Write([]byte(key + websocketGUID))
+	if header.Get("Sec-WebSocket-Accept") != base64.StdEncoding.EncodeToString(h.Sum(nil)) {
+		return errors.New("websocket: invalid Sec-WebSocket-Accept")
+	}
+
+	return nil
+}
+
+// headerHasToken reports whether a comma-separated header contains token
+func headerHasToken(header http.Header, name, token string) bool {
+	for _, value := range header.Values(name) {
+		for _, t := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(t), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// ReadMessage reads the next text or binary message, joining fragmented
+// frames. Once the server closes the connection a *WebSocketCloseError
+// is returned.
+func (c *WebSocketConn) ReadMessage() (messageType int, data []byte, err error) {
+	for {
+		fin, opcode, payload, err := c.readFrame()
+		if err != nil {
+			return 0, nil, err
+		}
+
+		switch opcode {
+		case opPing:
+			// A failed pong surfaces on the next read
+			_ = c.writeFrame(opPong, payload)
+			continue
+		case opPong:
+			continue
+		case opClose:
+			closeErr := &WebSocketCloseError{Code: 1005}
+			if len(payload) >= 2 {
+				closeErr.Code = int(binary.BigEndian.Uint16(payload))
+				closeErr.Reason = string(payload[2:])
+			}
+			_ = c.writeClose(closeErr.Code)
+			return 0, nil, closeErr
+		case opText, opBinary:
+			if messageType != 0 {
+				return 0, nil, errors.New("websocket: new message before previous one finished")
+			}
+			messageType = int(opcode)
+		case opContinuation:
+			if messageType == 0 {
+				return 0, nil, errors.New("websocket: unexpected continuation frame")
+			}
+		default:
+			return 0, nil, fmt.Errorf("websocket: unknown opcode %d", opcode)
+		}
+
+		data = append(data, payload...)
+		if int64(len(data)) > c.maxMessageSize() {
+			return 0, nil, ErrBodyTooLarge
+		}
+		if fin {
+			return messageType, data, nil
+		}
+	}
+}
+
+// WriteMessage sends data as a single text or binary message
+func (c *WebSocketConn) WriteMessage(messageType int, data []byte) error {
+	if messageType != WebSocketText && messageType != WebSocketBinary {
+		return fmt.Errorf("websocket: invalid message type %d", messageType)
+	}
+	return c.writeFrame(byte(messageType), data)
+}
+
+// Close sends a normal closure frame and closes the connection
+func (c *WebSocketConn) Close() error {
+	_ = c.writeClose(1000)
+	return c.conn.Close()
+}
+
+// writeClose sends a close frame with code, 1005 meaning no code
+func (c *WebSocketConn) writeClose(code int) error {
+	var payload []byte
+	if code != 1005 {
+		payload = binary.BigEndian.AppendUint16(nil, uint16(code)) // #nosec G115 -- close codes fit in 16 bits
+	}
+	return c.writeFrame(opClose, payload)
+}
+
+// readFrame reads a single frame from the server
+func (c *WebSocketConn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	var head [2]byte
+	if _, err := io.ReadFull(c.reader, head[:]); err != nil {
+		return false, 0, nil, fmt.Errorf("websocket: read frame: %w", err)
+	}
+
+	fin = head[0]&0x80 != 0
+	opcode = head[0] & 0x0f
+	masked := head[1]&0x80 != 0
+
+	length := uint64(head[1] & 0x7f)
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return false, 0, nil, fmt.Errorf("websocket: read frame: %w", err)
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err := io.ReadFull(c.reader, ext[:]); err != nil {
+			return false, 0, nil, fmt.Errorf("websocket: read frame: %w", err)
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+		if length>>63 != 0 {
+			return false, 0, nil, errors.New("websocket: invalid frame length")
+		}
+	}
+
+	if length > uint64(c.maxMessageSize()) {
+		return false, 0, nil, ErrBodyTooLarge
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err := io.ReadFull(c.reader, mask[:]); err != nil {
+			return false, 0, nil, fmt.Errorf("websocket: read frame: %w", err)
+		}
+	}
+
+	payload = make([]byte, length)
+	if _, err := io.ReadFull(c.reader, payload); err != nil {
+		return false, 0, nil, fmt.Errorf("websocket: read frame: %w", err)
+	}
+	if masked {
+		maskBytes(mask, payload)
+	}
+
+	return fin, opcode, payload, nil
+}
+
+// maxMessageSize returns the read limit, falling back to the default
+func (c *WebSocketConn) maxMessageSize() int64 {
+	if c.readLimit > 0 {
+		return c.readLimit
+	}
+	return defaultWebSocketReadLimit
+}
+
+// writeFrame sends a single masked frame. Nothing is sent after a close frame.
+func (c *WebSocketConn) writeFrame(opcode byte, payload []byte) error {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+
+	if c.closeSent {
+		return errors.New("websocket: connection is closing")
+	}
+	if opcode == opClose {
+		c.closeSent = true
+	}
+
+	frame := []byte{0x80 | opcode}
+	switch n := len(payload); {
+	case n < 126:
+		frame = append(frame, 0x80|byte(n))
+	case n <= 0xffff:
+		frame = append(frame, 0x80|126)
+		frame = binary.BigEndian.AppendUint16(frame, uint16(n))
+	default:
+		frame = append(frame, 0x80|127)
+		frame = binary.BigEndian.AppendUint64(frame, uint64(n))
+	}
+
+	var mask [4]byte
+	if _, err := rand.Read(mask[:]); err != nil {
+		return fmt.Errorf("websocket: generate mask: %w", err)
+	}
+	frame = append(frame, mask[:]...)
+
+	start := len(frame)
+	frame = append(frame, payload...)
+	maskBytes(mask, frame[start:])
+
+	if _, err := c.conn.Write(frame); err != nil {
+		return fmt.Errorf("websocket: write frame: %w", err)
+	}
+	return nil
+}
+
+// maskBytes applies the frame mask to data in place
+func maskBytes(mask [4]byte, data []byte) {
+	for i := range data {
+		data[i] ^= mask[i%4]
+	}
+}
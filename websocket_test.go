@@ -0,0 +1,191 @@
+package rq
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// serveWebSocketEcho upgrades the connection, pings the client once and
+// echoes every message until the client closes
+func serveWebSocketEcho(t *testing.T, w http.ResponseWriter, r *http.Request) {
+	h := sha1.New()
+	h.Write([]byte(r.Header.Get("Sec-WebSocket-Key") + websocketGUID))
+	accept := base64.StdEncoding.EncodeToString(h.Sum(nil))
+
+	conn, rw, err := w.(http.Hijacker).Hijack()
+	if err != nil {
+		t.Error(err)
+		return
+	}
+	defer conn.Close()
+
+	rw.WriteString("HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\nConnection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + accept + "\r\n\r\n")
+	writeServerFrame(rw.Writer, opPing, []byte("hi"))
+	rw.Flush()
+
+	for {
+		opcode, payload, err := readClientFrame(rw.Reader)
+		if err != nil {
+			return
+		}
+		switch opcode {
+		case opPong:
+			if string(payload) != "hi" {
+				t.Errorf("want pong payload hi, got %q", payload)
+			}
+		case opClose:
+			writeServerFrame(rw.Writer, opClose, payload)
+			rw.Flush()
+			return
+		default:
+			writeServerFrame(rw.Writer, opcode, payload)
+			rw.Flush()
+		}
+	}
+}
+
+func writeServerFrame(w *bufio.Writer, opcode byte, payload []byte) {
+	w.WriteByte(0x80 | opcode)
+	if len(payload) < 126 {
+		w.WriteByte(byte(len(payload)))
+	} else {
+		w.WriteByte(126)
+		w.Write(binary.BigEndian.AppendUint16(nil, uint16(len(payload))))
+	}
+	w.Write(payload)
+}
+
+func readClientFrame(r *bufio.Reader) (byte, []byte, error) {
+	var head [2]byte
+	if _, err := io.ReadFull(r, head[:]); err != nil {
+		return 0, nil, err
+	}
+	length := int(head[1] & 0x7f)
+	if length == 126 {
+		var ext [2]byte
+		io.ReadFull(r, ext[:])
+		length = int(binary.BigEndian.Uint16(ext[:]))
+	}
+	var mask [4]byte
+	io.ReadFull(r, mask[:])
+	payload := make([]byte, length)
+	if _, err := io.ReadFull(r, payload); err != nil {
+		return 0, nil, err
+	}
+	maskBytes(mask, payload)
+	return head[0] & 0x0f, payload, nil
+}
+
+func TestWebSocket(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		serveWebSocketEcho(t, w, r)
+	}))
+	defer srv.Close()
+
+	wsURL := "ws" + strings.TrimPrefix(srv.URL, "http")
+
+	t.Run("echo", func(t *testing.T) {
+		conn, resp, err := Get(wsURL).BearerToken("token").Timeout(50 * time.Millisecond).WebSocket(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		if resp.StatusCode != http.StatusSwitchingProtocols {
+			t.Errorf("want status 101, got %d", resp.StatusCode)
+		}
+
+		// The handshake timeout must not end the connection
+		time.Sleep(100 * time.Millisecond)
+
+		long := strings.Repeat("x", 300)
+		for _, msg := range []string{"hello", long} {
+			if err := conn.WriteMessage(WebSocketText, []byte(msg)); err != nil {
+				t.Fatal(err)
+			}
+			typ, data, err := conn.ReadMessage()
+			if err != nil {
+				t.Fatal(err)
+			}
+			if typ != WebSocketText || string(data) != msg {
+				t.Errorf("want text %d bytes, got type %d with %d bytes", len(msg), typ, len(data))
+			}
+		}
+	})
+
+	t.Run("server close", func(t *testing.T) {
+		conn, _, err := Get(wsURL).BearerToken("token").WebSocket(context.Background())
+		if err != nil {
+			t.Fatal(err)
+		}
+		defer conn.Close()
+
+		if err := conn.writeClose(1001); err != nil {
+			t.Fatal(err)
+		}
+
+		var closeErr *WebSocketCloseError
+		if _, _, err := conn.ReadMessage(); !errors.As(err, &closeErr) || closeErr.Code != 1001 {
+			t.Errorf("want close error with code 1001, got %v", err)
+		}
+	})
+
+	t.Run("rejected handshake", func(t *testing.T) {
+		conn, resp, err := Get(wsURL).WebSocket(context.Background())
+		if err == nil {
+			conn.Close()
+			t.Fatal("want handshake error, got nil")
+		}
+		if resp == nil || resp.StatusCode != http.StatusForbidden {
+			t.Errorf("want response with status 403, got %v", resp)
+		}
+	})
+}
+
+func TestWebSocketFrameLength(t *testing.T) {
+	tests := map[string]struct {
+		readLimit int64
+		length    uint64
+		wantErr   error
+	}{
+		"default limit":  {length: defaultWebSocketReadLimit + 1, wantErr: ErrBodyTooLarge},
+		"explicit limit": {readLimit: 10, length: 11, wantErr: ErrBodyTooLarge},
+		"high bit set":   {length: 1 << 63},
+	}
+
+	for name, tc := range tests {
+		t.Run(name, func(t *testing.T) {
+			frame := []byte{0x80 | opBinary, 127}
+			frame = binary.BigEndian.AppendUint64(frame, tc.length)
+			conn := &WebSocketConn{
+				reader:    bufio.NewReader(bytes.NewReader(frame)),
+				readLimit: tc.readLimit,
+			}
+
+			_, _, _, err := conn.readFrame()
+			if err == nil {
+				t.Fatal("want error, got nil")
+			}
+			if tc.wantErr != nil && !errors.Is(err, tc.wantErr) {
+				t.Errorf("want %v, got %v", tc.wantErr, err)
+			}
+		})
+	}
+}